@@ -1,9 +1,11 @@
 package googleplayscraper
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,7 +17,11 @@ type AppOptions struct {
 	Country string
 }
 
-// App fetches application details
+// App fetches application details.
+// Listings that exist but cannot be installed (region restricted, incompatible)
+// are returned together with an *UnavailableError; removed apps return only the error.
+// For languages other than English, telling region-restricted and incompatible
+// listings apart takes an extra request for the English page.
 func (c *Client) App(ctx context.Context, appID string, opts AppOptions) (*App, error) {
 	if appID == "" {
 		return nil, fmt.Errorf("appID is required")
@@ -32,206 +38,128 @@ func (c *Client) App(ctx context.Context, appID string, opts AppOptions) (*App,
 
 	body, err := c.get(ctx, url)
 	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return nil, &UnavailableError{AppID: appID, Country: opts.Country, Availability: AvailabilityNotFound}
+		}
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	return parseAppPage(body, appID, url)
-}
-
-var scriptDataRegex = regexp.MustCompile(`AF_initDataCallback\(\{key:\s*'(ds:\d+)'.*?data:(.*?), sideChannel:`)
-
-func parseAppPage(body []byte, appID, pageURL string) (*App, error) {
-	html := string(body)
-
-	// Find all script data blocks
-	dataBlocks := make(map[string]interface{})
-	matches := scriptDataRegex.FindAllStringSubmatch(html, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 {
-			continue
+	rep := c.newReport(EndpointDetails, url)
+	app, err := parseAppPage(body, appID, url, rep)
+	if err != nil {
+		c.sendReport(rep, 0)
+		// A page without app data but with an explanation is an unavailable listing
+		availability := availabilityFromPage(body)
+		if availability == "" && opts.Lang != "en" {
+			availability = c.englishAvailability(ctx, appID, opts.Country)
 		}
-		key := match[1]
-		dataStr := strings.TrimSpace(match[2])
-
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			continue
+		if availability != "" {
+			return nil, &UnavailableError{AppID: appID, Country: opts.Country, Availability: availability}
 		}
-		dataBlocks[key] = data
-	}
-
-	return extractAppData(dataBlocks, appID, pageURL)
-}
-
-func extractAppData(data map[string]interface{}, appID, url string) (*App, error) {
-	app := &App{
-		AppID:     appID,
-		URL:       url,
-		Available: true,
-	}
-
-	// Main data is in ds:5
-	ds5, ok := data["ds:5"]
-	if !ok {
-		return nil, fmt.Errorf("main data block not found")
+		return nil, err
 	}
-
-	// Navigate: [1][2] contains app info
-	appData := getPath(ds5, 1, 2)
-	if appData == nil {
-		return nil, fmt.Errorf("app data not found")
-	}
-
-	// Title: [0][0]
-	if v := getPath(appData, 0, 0); v != nil {
-		app.Title = toString(v)
+	if err := c.sendReport(rep, 1); err != nil {
+		return nil, err
 	}
 
-	// Description HTML: [72][0][1]
-	if v := getPath(appData, 72, 0, 1); v != nil {
-		app.DescriptionHTML = toString(v)
-		app.Description = stripHTML(app.DescriptionHTML)
+	if !c.rawData {
+		app.Raw = nil
 	}
 
-	// Summary: [73][0][1]
-	if v := getPath(appData, 73, 0, 1); v != nil {
-		app.Summary = toString(v)
-	}
-
-	// Installs: [13][0]
-	if v := getPath(appData, 13, 0); v != nil {
-		app.Installs = toString(v)
-	}
-
-	// MinInstalls: [13][1]
-	if v := getPath(appData, 13, 1); v != nil {
-		app.MinInstalls = toInt64(v)
-	}
-
-	// MaxInstalls: [13][2]
-	if v := getPath(appData, 13, 2); v != nil {
-		app.MaxInstalls = toInt64(v)
-	}
-
-	// Score: [51][0][1]
-	if v := getPath(appData, 51, 0, 1); v != nil {
-		app.Score = toFloat64(v)
-	}
-
-	// ScoreText: [51][0][0]
-	if v := getPath(appData, 51, 0, 0); v != nil {
-		app.ScoreText = toString(v)
-	}
-
-	// Ratings: [51][2][1]
-	if v := getPath(appData, 51, 2, 1); v != nil {
-		app.Ratings = toInt(v)
-	}
-
-	// Reviews count: [51][3][1]
-	if v := getPath(appData, 51, 3, 1); v != nil {
-		app.Reviews = toInt(v)
-	}
-
-	// Histogram: [51][1]
-	if hist := getPath(appData, 51, 1); hist != nil {
-		app.Histogram = extractHistogram(hist)
-	}
-
-	// Price: [57][0][0][0][0][1][0][0]
-	if v := getPath(appData, 57, 0, 0, 0, 0, 1, 0, 0); v != nil {
-		price := toFloat64(v)
-		app.Price = price / 1000000
-		app.Free = price == 0
-	} else {
-		app.Free = true
-	}
-
-	// Currency: [57][0][0][0][0][1][0][1]
-	if v := getPath(appData, 57, 0, 0, 0, 0, 1, 0, 1); v != nil {
-		app.Currency = toString(v)
-	}
-
-	// PriceText: [57][0][0][0][0][1][0][2]
-	if v := getPath(appData, 57, 0, 0, 0, 0, 1, 0, 2); v != nil {
-		app.PriceText = toString(v)
-	}
-
-	// Developer: [68][0]
-	if v := getPath(appData, 68, 0); v != nil {
-		app.Developer = toString(v)
-	}
-
-	// DeveloperID: [68][1][4][2]
-	if v := getPath(appData, 68, 1, 4, 2); v != nil {
-		app.DeveloperID = toString(v)
+	// The page text explaining why a listing can't be installed is only
+	// recognised in English
+	if app.Availability == AvailabilityUnavailable && opts.Lang != "en" {
+		if availability := c.englishAvailability(ctx, appID, opts.Country); availability != "" {
+			app.Availability = availability
+		}
 	}
 
-	// DeveloperEmail: [69][1][0]
-	if v := getPath(appData, 69, 1, 0); v != nil {
-		app.DeveloperEmail = toString(v)
+	switch app.Availability {
+	case AvailabilityAvailable, AvailabilityPreRegistration:
+		return app, nil
+	default:
+		return app, &UnavailableError{AppID: appID, Country: opts.Country, Availability: app.Availability}
 	}
+}
 
-	// DeveloperWebsite: [69][0][5][2]
-	if v := getPath(appData, 69, 0, 5, 2); v != nil {
-		app.DeveloperWebsite = toString(v)
-	}
+func parseAppPage(body []byte, appID, pageURL string, rep *ParseReport) (*App, error) {
+	dataBlocks := parseDataBlocks(body)
 
-	// DeveloperAddress: [69][2][0]
-	if v := getPath(appData, 69, 2, 0); v != nil {
-		app.DeveloperAddress = toString(v)
+	app, err := extractAppData(dataBlocks, appID, pageURL, rep)
+	if err != nil {
+		return nil, err
 	}
 
-	// Genre: [79][0][0][0]
-	if v := getPath(appData, 79, 0, 0, 0); v != nil {
-		app.Genre = toString(v)
+	// The data block only says the app can't be installed; the page text says why
+	if app.Availability == AvailabilityUnavailable {
+		if availability := availabilityFromPage(body); availability != "" {
+			app.Availability = availability
+		}
 	}
 
-	// GenreID: [79][0][0][2]
-	if v := getPath(appData, 79, 0, 0, 2); v != nil {
-		app.GenreID = toString(v)
-	}
+	return app, nil
+}
 
-	// Icon: [95][0][3][2]
-	if v := getPath(appData, 95, 0, 3, 2); v != nil {
-		app.Icon = toString(v)
+// englishAvailability reads why a listing can't be installed from its
+// English page, returning "" when the page doesn't say
+func (c *Client) englishAvailability(ctx context.Context, appID, country string) Availability {
+	url := fmt.Sprintf("%s/store/apps/details?id=%s&hl=en&gl=%s", BaseURL, appID, country)
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return ""
 	}
+	return availabilityFromPage(body)
+}
 
-	// Version: [140][0][0][0]
-	if v := getPath(appData, 140, 0, 0, 0); v != nil {
-		app.Version = toString(v)
-	}
+// unavailableMarkers map English messages Google Play shows on listings that can't be installed
+var unavailableMarkers = []struct {
+	text         string
+	availability Availability
+}{
+	{"isn't available in your country", AvailabilityRegionRestricted},
+	{"is not available in your country", AvailabilityRegionRestricted},
+	{"isn't compatible with any of your devices", AvailabilityIncompatible},
+	{"isn't compatible with your device", AvailabilityIncompatible},
+	{"is not compatible with your device", AvailabilityIncompatible},
+}
 
-	// AndroidVersion: [140][1][1][0][0][1]
-	if v := getPath(appData, 140, 1, 1, 0, 0, 1); v != nil {
-		app.AndroidVersion = toString(v)
+func availabilityFromPage(body []byte) Availability {
+	for _, m := range unavailableMarkers {
+		if bytes.Contains(body, []byte(m.text)) {
+			return m.availability
+		}
 	}
+	return ""
+}
 
-	// ContentRating: [9][0]
-	if v := getPath(appData, 9, 0); v != nil {
-		app.ContentRating = toString(v)
+func extractAppData(data map[string]interface{}, appID, url string, rep *ParseReport) (*App, error) {
+	app := &App{
+		AppID: appID,
+		URL:   url,
 	}
 
-	// Released: [10][1][0]
-	if v := getPath(appData, 10, 1, 0); v != nil {
-		app.Released = toString(v)
+	// Main data is in ds:5
+	ds5, ok := data["ds:5"]
+	if !ok {
+		return nil, fmt.Errorf("main data block not found")
 	}
 
-	// Updated: [145][0][1][0]
-	if v := getPath(appData, 145, 0, 1, 0); v != nil {
-		app.Updated = toInt64(v)
+	// Navigate: [1][2] contains app info
+	appData := getPath(ds5, 1, 2)
+	if appData == nil {
+		return nil, fmt.Errorf("app data not found")
 	}
+	rep.at("ds:5[1][2]")
 
-	// Screenshots
-	if screenshots := getPath(appData, 78, 0); screenshots != nil {
-		app.Screenshots = extractScreenshots(screenshots)
-	}
+	// Fields absent from the block keep these defaults
+	app.Availability = AvailabilityUnavailable
+	app.Free = true
+	applyFields(app, appData, appFields, rep)
+	app.PreRegister = app.Availability == AvailabilityPreRegistration
 
-	// PrivacyPolicy: [99][0][5][2]
-	if v := getPath(appData, 99, 0, 5, 2); v != nil {
-		app.PrivacyPolicy = toString(v)
-	}
+	app.Media = extractMedia(appData, rep)
+	app.Raw = &RawData{Blocks: data, Node: appData}
 
 	return app, nil
 }
@@ -241,7 +169,7 @@ func getPath(data interface{}, indices ...int) interface{} {
 	for _, idx := range indices {
 		switch v := current.(type) {
 		case []interface{}:
-			if idx >= len(v) {
+			if idx < 0 || idx >= len(v) {
 				return nil
 			}
 			current = v[idx]
@@ -270,6 +198,9 @@ func toString(v interface{}) string {
 	return fmt.Sprintf("%v", v)
 }
 
+// toInt, toInt64 and toFloat64 convert decoded JSON numbers and numeric
+// strings, returning 0 for anything else. ParseReport.number reports the
+// strings they can't convert.
 func toInt(v interface{}) int {
 	if v == nil {
 		return 0
@@ -280,8 +211,8 @@ func toInt(v interface{}) int {
 	case int:
 		return n
 	case string:
-		i, _ := strconv.Atoi(n)
-		return i
+		f, _ := strconv.ParseFloat(n, 64)
+		return int(f)
 	}
 	return 0
 }
@@ -298,8 +229,8 @@ func toInt64(v interface{}) int64 {
 	case int:
 		return int64(n)
 	case string:
-		i, _ := strconv.ParseInt(n, 10, 64)
-		return i
+		f, _ := strconv.ParseFloat(n, 64)
+		return int64(f)
 	}
 	return 0
 }
@@ -320,6 +251,13 @@ func toFloat64(v interface{}) float64 {
 	return 0
 }
 
+// checkHistogram reports star counts of a histogram that aren't numbers
+func checkHistogram(data interface{}, rep *ParseReport) {
+	for i := 0; i < 5; i++ {
+		rep.number("Histogram", getPath(data, i, 1), []int{i, 1})
+	}
+}
+
 func extractHistogram(data interface{}) [5]int {
 	var hist [5]int
 	arr, ok := data.([]interface{})
@@ -352,6 +290,73 @@ func extractScreenshots(data interface{}) []string {
 	return screenshots
 }
 
+// screenshotPaths lists where each form factor's screenshot set lives in the
+// app data block. Only the default phone set at [78][0] is mapped; the entries
+// after it haven't been checked against real pages with device-specific sets.
+var screenshotPaths = []struct {
+	formFactor FormFactor
+	path       []int
+}{
+	{FormFactorPhone, []int{78, 0}},
+}
+
+func extractMedia(appData interface{}, rep *ParseReport) Media {
+	// image reads the entry at path, reporting sizes that aren't numbers
+	image := func(path ...int) Image {
+		item := getPath(appData, path...)
+		prev := rep.enter(path)
+		rep.number("Media", getPath(item, 2, 0), []int{2, 0})
+		rep.number("Media", getPath(item, 2, 1), []int{2, 1})
+		rep.leave(prev)
+		return extractImage(item)
+	}
+
+	media := Media{
+		Icon:        image(95, 0),
+		HeaderImage: image(96, 0),
+		VideoImage:  image(100, 1, 0),
+	}
+
+	if v := getPath(appData, 100, 0, 0, 3, 2); v != nil {
+		media.Video = toString(v)
+	}
+
+	for _, sp := range screenshotPaths {
+		arr, ok := getPath(appData, sp.path...).([]interface{})
+		if !ok {
+			continue
+		}
+		var images []Image
+		for i := range arr {
+			if img := image(append(append([]int{}, sp.path...), i)...); img.URL != "" {
+				images = append(images, img)
+			}
+		}
+		if len(images) == 0 {
+			continue
+		}
+		if media.Screenshots == nil {
+			media.Screenshots = make(map[FormFactor][]Image)
+		}
+		media.Screenshots[sp.formFactor] = images
+	}
+
+	return media
+}
+
+// extractImage reads an image entry: [2] holds [width, height], [3][2] the URL
+func extractImage(data interface{}) Image {
+	url, ok := getPath(data, 3, 2).(string)
+	if !ok {
+		return Image{}
+	}
+	return Image{
+		URL:    url,
+		Width:  toInt(getPath(data, 2, 0)),
+		Height: toInt(getPath(data, 2, 1)),
+	}
+}
+
 var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
 
 func stripHTML(s string) string {
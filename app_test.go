@@ -2,6 +2,11 @@ package googleplayscraper
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
 	"testing"
 )
 
@@ -114,6 +119,202 @@ func TestExtractHistogram(t *testing.T) {
 	}
 }
 
+func TestExtractMedia(t *testing.T) {
+	image := func(url string, w, h float64) interface{} {
+		return []interface{}{nil, float64(2), []interface{}{w, h}, []interface{}{nil, nil, url}}
+	}
+
+	appData := make([]interface{}, 101)
+	appData[78] = []interface{}{
+		[]interface{}{image("phone1", 1080, 1920), image("phone2", 1080, 1920)},
+		[]interface{}{image("tablet1", 2560, 1600)},
+	}
+	appData[95] = []interface{}{image("icon", 512, 512)}
+	appData[96] = []interface{}{image("header", 1024, 500)}
+	appData[100] = []interface{}{
+		[]interface{}{[]interface{}{nil, nil, nil, []interface{}{nil, nil, "video"}}},
+		[]interface{}{image("poster", 1280, 720)},
+	}
+
+	media := extractMedia(appData, nil)
+
+	if media.Icon != (Image{URL: "icon", Width: 512, Height: 512}) {
+		t.Errorf("Icon: got %+v", media.Icon)
+	}
+	if media.HeaderImage != (Image{URL: "header", Width: 1024, Height: 500}) {
+		t.Errorf("HeaderImage: got %+v", media.HeaderImage)
+	}
+	if media.Video != "video" {
+		t.Errorf("Video: got %q, want %q", media.Video, "video")
+	}
+	if media.VideoImage.URL != "poster" {
+		t.Errorf("VideoImage: got %+v", media.VideoImage)
+	}
+	if got := media.Screenshots[FormFactorPhone]; len(got) != 2 || got[1].URL != "phone2" || got[1].Height != 1920 {
+		t.Errorf("phone screenshots: got %+v", got)
+	}
+	if len(media.Screenshots) != 1 {
+		t.Errorf("only phone screenshots are mapped, got %+v", media.Screenshots)
+	}
+}
+
+func TestExtractImageMalformed(t *testing.T) {
+	if img := extractImage(nil); img.URL != "" {
+		t.Errorf("expected empty image, got %+v", img)
+	}
+	if img := extractImage([]interface{}{nil, nil, nil, []interface{}{nil, nil, "url"}}); img != (Image{URL: "url"}) {
+		t.Errorf("expected URL without dimensions, got %+v", img)
+	}
+}
+
+// appPage wraps appData into a details page the way Google Play embeds it
+func appPage(t *testing.T, appData []interface{}, extraHTML string) []byte {
+	t.Helper()
+	data, err := json.Marshal([]interface{}{nil, []interface{}{nil, nil, appData}})
+	if err != nil {
+		t.Fatalf("marshal app data: %v", err)
+	}
+	return []byte(fmt.Sprintf(`<html>%s<script>AF_initDataCallback({key: 'ds:5', hash: '1', data:%s, sideChannel: {}});</script></html>`, extraHTML, data))
+}
+
+func TestParseAppPageAvailability(t *testing.T) {
+	appData := make([]interface{}, 19)
+	appData[0] = []interface{}{"Example"}
+
+	tests := []struct {
+		name      string
+		status    interface{}
+		extra     string
+		want      Availability
+		available bool
+	}{
+		{"available", float64(2), "", AvailabilityAvailable, true},
+		{"pre-registration", float64(1), "", AvailabilityPreRegistration, false},
+		{"region restricted", nil, "This item isn't available in your country.", AvailabilityRegionRestricted, false},
+		{"incompatible", nil, "This app isn't compatible with any of your devices.", AvailabilityIncompatible, false},
+		{"unknown reason", nil, "", AvailabilityUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appData[18] = []interface{}{tt.status}
+			app, err := parseAppPage(appPage(t, appData, tt.extra), "com.example", "url", nil)
+			if err != nil {
+				t.Fatalf("parseAppPage failed: %v", err)
+			}
+			if app.Availability != tt.want {
+				t.Errorf("Availability: got %q, want %q", app.Availability, tt.want)
+			}
+			if app.Available != tt.available {
+				t.Errorf("Available: got %v, want %v", app.Available, tt.available)
+			}
+		})
+	}
+}
+
+func TestAvailabilityFromPageWithoutData(t *testing.T) {
+	body := []byte("<html>This item isn't available in your country.</html>")
+	if _, err := parseAppPage(body, "com.example", "url", nil); err == nil {
+		t.Fatal("expected parse error for page without data")
+	}
+	if got := availabilityFromPage(body); got != AvailabilityRegionRestricted {
+		t.Errorf("got %q, want %q", got, AvailabilityRegionRestricted)
+	}
+}
+
+// langTransport serves a page per hl parameter
+type langTransport struct {
+	pages map[string][]byte
+	langs []string
+}
+
+func (l *langTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	lang := req.URL.Query().Get("hl")
+	l.langs = append(l.langs, lang)
+	return (&fixtureTransport{body: l.pages[lang]}).RoundTrip(req)
+}
+
+func TestAppAvailabilityInOtherLanguages(t *testing.T) {
+	appData := make([]interface{}, 19)
+	appData[0] = []interface{}{"Beispiel"}
+	appData[18] = []interface{}{nil}
+	tr := &langTransport{pages: map[string][]byte{
+		"de": appPage(t, appData, "Dieser Artikel ist in deinem Land nicht verfügbar."),
+		"en": appPage(t, appData, "This item isn't available in your country."),
+	}}
+
+	app, err := NewClient(WithTransport(tr)).App(context.Background(), "com.example", AppOptions{Lang: "de"})
+	if !errors.Is(err, ErrRegionRestricted) {
+		t.Fatalf("expected ErrRegionRestricted, got %v", err)
+	}
+	if app == nil || app.Title != "Beispiel" {
+		t.Fatalf("expected the German listing, got %+v", app)
+	}
+	if len(tr.langs) != 2 || tr.langs[1] != "en" {
+		t.Errorf("requests: %v", tr.langs)
+	}
+
+	tr.langs = nil
+	if _, err := NewClient(WithTransport(tr)).App(context.Background(), "com.example", AppOptions{Lang: "en"}); !errors.Is(err, ErrRegionRestricted) {
+		t.Errorf("English: %v", err)
+	}
+	if len(tr.langs) != 1 {
+		t.Errorf("English page should be fetched once, got %v", tr.langs)
+	}
+}
+
+func TestUnavailableError(t *testing.T) {
+	tests := []struct {
+		availability Availability
+		want         error
+	}{
+		{AvailabilityNotFound, ErrNotFound},
+		{AvailabilityRegionRestricted, ErrRegionRestricted},
+		{AvailabilityIncompatible, ErrIncompatible},
+		{AvailabilityUnavailable, ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		var err error = &UnavailableError{AppID: "com.example", Country: "us", Availability: tt.availability}
+		wrapped := fmt.Errorf("lookup: %w", err)
+		if !errors.Is(wrapped, tt.want) {
+			t.Errorf("%s: errors.Is(%v) = false", tt.availability, tt.want)
+		}
+		var unavailable *UnavailableError
+		if !errors.As(wrapped, &unavailable) || unavailable.Availability != tt.availability {
+			t.Errorf("%s: errors.As failed", tt.availability)
+		}
+	}
+}
+
+func TestParseAppPagePreRegistration(t *testing.T) {
+	body, err := os.ReadFile("testdata/preregister.html")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	app, err := parseAppPage(body, "com.nebula.starfall", "url", nil)
+	if err != nil {
+		t.Fatalf("parseAppPage failed: %v", err)
+	}
+
+	if app.Title != "Starfall Saga" {
+		t.Errorf("Title: got %q", app.Title)
+	}
+	if !app.PreRegister {
+		t.Error("PreRegister should be true")
+	}
+	if app.Available {
+		t.Error("pre-registration app should not be installable")
+	}
+	if app.Availability != AvailabilityPreRegistration {
+		t.Errorf("Availability: got %q", app.Availability)
+	}
+	if app.Developer != "Nebula Games" || app.Genre != "Role Playing" {
+		t.Errorf("Developer/Genre: got %q / %q", app.Developer, app.Genre)
+	}
+}
+
 func TestStripHTML(t *testing.T) {
 	tests := []struct {
 		input string
@@ -177,6 +378,9 @@ func TestAppNotFound(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for non-existent app")
 	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
 	t.Logf("Got expected error: %v", err)
 }
 
@@ -0,0 +1,139 @@
+package artwork
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+// DefaultThreshold is the Hamming distance above which two images are
+// considered visually different. Re-encodes and resizes typically stay below 6.
+const DefaultThreshold = 10
+
+// fetchSize is the size images are requested at before hashing.
+// The hash is scale invariant, so a small JPEG keeps downloads cheap.
+var fetchSize = gplay.ImageSize{Width: 256, Format: gplay.ImageFormatJPEG}
+
+// Fingerprint is the perceptual hash of a single listing image
+type Fingerprint struct {
+	URL  string `json:"url"`
+	Hash Hash   `json:"hash"`
+}
+
+// Snapshot holds fingerprints of an app's icon and screenshots at a point in time
+type Snapshot struct {
+	AppID       string        `json:"appId"`
+	CapturedAt  time.Time     `json:"capturedAt"`
+	Icon        Fingerprint   `json:"icon"`
+	Screenshots []Fingerprint `json:"screenshots"`
+}
+
+// ChangeKind describes what kind of visual change was detected
+type ChangeKind string
+
+const (
+	IconChanged       ChangeKind = "icon_changed"
+	ScreenshotAdded   ChangeKind = "screenshot_added"
+	ScreenshotRemoved ChangeKind = "screenshot_removed"
+)
+
+// Change is a single visual difference between two snapshots
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Index    int        `json:"index"`              // Screenshot position in the snapshot it belongs to
+	Distance int        `json:"distance,omitempty"` // Hamming distance for icon changes
+	OldURL   string     `json:"oldUrl,omitempty"`
+	NewURL   string     `json:"newUrl,omitempty"`
+}
+
+// Capture downloads the icon and phone screenshots of app and fingerprints them
+func Capture(ctx context.Context, client *gplay.Client, app *gplay.App) (*Snapshot, error) {
+	if app == nil {
+		return nil, fmt.Errorf("app is required")
+	}
+
+	snap := &Snapshot{
+		AppID:      app.AppID,
+		CapturedAt: time.Now().UTC(),
+	}
+
+	if app.Icon != "" {
+		fp, err := fingerprint(ctx, client, app.Icon)
+		if err != nil {
+			return nil, fmt.Errorf("icon: %w", err)
+		}
+		snap.Icon = fp
+	}
+
+	for i, u := range app.Screenshots {
+		fp, err := fingerprint(ctx, client, u)
+		if err != nil {
+			return nil, fmt.Errorf("screenshot %d: %w", i, err)
+		}
+		snap.Screenshots = append(snap.Screenshots, fp)
+	}
+
+	return snap, nil
+}
+
+func fingerprint(ctx context.Context, client *gplay.Client, rawURL string) (Fingerprint, error) {
+	data, _, err := client.FetchAsset(ctx, gplay.ImageURL(rawURL, fetchSize))
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	h, err := HashBytes(data)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	return Fingerprint{URL: rawURL, Hash: h}, nil
+}
+
+// Compare reports visual changes between two snapshots of the same app.
+// Screenshots are matched by appearance rather than position, so reordering
+// alone is not reported. A threshold <= 0 uses DefaultThreshold.
+func Compare(before, after *Snapshot, threshold int) []Change {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+
+	var changes []Change
+
+	if before.Icon.URL != "" && after.Icon.URL != "" {
+		if d := before.Icon.Hash.Distance(after.Icon.Hash); d > threshold {
+			changes = append(changes, Change{
+				Kind:     IconChanged,
+				Distance: d,
+				OldURL:   before.Icon.URL,
+				NewURL:   after.Icon.URL,
+			})
+		}
+	}
+
+	matched := make([]bool, len(before.Screenshots))
+	for i, n := range after.Screenshots {
+		best, bestDist := -1, threshold+1
+		for j, o := range before.Screenshots {
+			if matched[j] {
+				continue
+			}
+			if d := o.Hash.Distance(n.Hash); d < bestDist {
+				best, bestDist = j, d
+			}
+		}
+		if best < 0 {
+			changes = append(changes, Change{Kind: ScreenshotAdded, Index: i, NewURL: n.URL})
+			continue
+		}
+		matched[best] = true
+	}
+
+	for j, o := range before.Screenshots {
+		if !matched[j] {
+			changes = append(changes, Change{Kind: ScreenshotRemoved, Index: j, OldURL: o.URL})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,188 @@
+package artwork
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+// pattern draws a deterministic 8x8 grid of gray blocks; each seed gives a
+// different layout, while the same seed gives the same picture at any size
+func pattern(w, h int, seed int64) image.Image {
+	rng := rand.New(rand.NewSource(seed))
+	var cells [8][8]uint8
+	for y := range cells {
+		for x := range cells[y] {
+			cells[y][x] = uint8(rng.Intn(256))
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := cells[y*8/h][x*8/w]
+			img.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestHashStableAcrossScaleAndFormat(t *testing.T) {
+	small := HashImage(pattern(64, 64, 0))
+	large := HashImage(pattern(300, 300, 0))
+	if d := small.Distance(large); d > 4 {
+		t.Errorf("same image at different sizes: distance %d", d)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, pattern(128, 128, 0), &jpeg.Options{Quality: 60}); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+	fromJPEG, err := HashBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("HashBytes: %v", err)
+	}
+	if d := small.Distance(fromJPEG); d > 6 {
+		t.Errorf("same image after JPEG re-encode: distance %d", d)
+	}
+}
+
+func TestHashDiffersForDifferentImages(t *testing.T) {
+	a := HashImage(pattern(128, 128, 0))
+	b := HashImage(pattern(128, 128, 1))
+	c := HashImage(pattern(128, 128, 2))
+	if d := a.Distance(b); d <= DefaultThreshold {
+		t.Errorf("seed 0 vs seed 1: distance %d", d)
+	}
+	if d := a.Distance(c); d <= DefaultThreshold {
+		t.Errorf("seed 0 vs seed 2: distance %d", d)
+	}
+}
+
+func TestHashBytesInvalid(t *testing.T) {
+	if _, err := HashBytes([]byte("not an image")); err == nil {
+		t.Error("expected error for undecodable data")
+	}
+}
+
+func TestHashJSON(t *testing.T) {
+	fp := Fingerprint{URL: "u", Hash: 0xdeadbeef}
+	data, err := json.Marshal(fp)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"00000000deadbeef"`)) {
+		t.Errorf("hash not hex encoded: %s", data)
+	}
+	var decoded Fingerprint
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded != fp {
+		t.Errorf("round trip: got %+v, want %+v", decoded, fp)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	h0 := HashImage(pattern(64, 64, 0))
+	h1 := HashImage(pattern(64, 64, 1))
+	h2 := HashImage(pattern(64, 64, 2))
+
+	before := &Snapshot{
+		Icon:        Fingerprint{URL: "icon-a", Hash: h0},
+		Screenshots: []Fingerprint{{URL: "s0", Hash: h0}, {URL: "s1", Hash: h1}},
+	}
+
+	// Reordered screenshots with new URLs are not a change
+	same := &Snapshot{
+		Icon:        Fingerprint{URL: "icon-b", Hash: h0},
+		Screenshots: []Fingerprint{{URL: "x1", Hash: h1}, {URL: "x0", Hash: h0}},
+	}
+	if changes := Compare(before, same, 0); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+
+	after := &Snapshot{
+		Icon:        Fingerprint{URL: "icon-c", Hash: h2},
+		Screenshots: []Fingerprint{{URL: "s1", Hash: h1}, {URL: "s2", Hash: h2}},
+	}
+	changes := Compare(before, after, 0)
+
+	kinds := make(map[ChangeKind]Change)
+	for _, c := range changes {
+		kinds[c.Kind] = c
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	if c := kinds[IconChanged]; c.OldURL != "icon-a" || c.NewURL != "icon-c" {
+		t.Errorf("icon change: got %+v", c)
+	}
+	if c := kinds[ScreenshotAdded]; c.NewURL != "s2" || c.Index != 1 {
+		t.Errorf("added: got %+v", c)
+	}
+	if c := kinds[ScreenshotRemoved]; c.OldURL != "s0" || c.Index != 0 {
+		t.Errorf("removed: got %+v", c)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	images := map[string][]byte{
+		"/icon": encodePNG(t, pattern(64, 64, 2)),
+		"/s0":   encodePNG(t, pattern(64, 128, 0)),
+		"/s1":   encodePNG(t, pattern(64, 128, 1)),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := images[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	app := &gplay.App{
+		AppID:       "com.example.app",
+		Icon:        server.URL + "/icon",
+		Screenshots: []string{server.URL + "/s0", server.URL + "/s1"},
+	}
+
+	snap, err := Capture(context.Background(), gplay.NewClient(), app)
+	if err != nil {
+		t.Fatalf("Capture failed: %v", err)
+	}
+	if snap.AppID != app.AppID {
+		t.Errorf("AppID: got %q", snap.AppID)
+	}
+	if len(snap.Screenshots) != 2 {
+		t.Fatalf("expected 2 screenshots, got %d", len(snap.Screenshots))
+	}
+	if snap.Icon.Hash != HashImage(pattern(64, 64, 2)) {
+		t.Error("icon hash mismatch")
+	}
+
+	app.Screenshots = append(app.Screenshots, server.URL+"/missing")
+	if _, err := Capture(context.Background(), gplay.NewClient(), app); err == nil {
+		t.Error("expected error for missing screenshot")
+	}
+}
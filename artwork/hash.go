@@ -0,0 +1,148 @@
+// Package artwork detects visual changes in store listing icons and screenshots
+// using perceptual hashes computed with the standard library image decoders.
+package artwork
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF decoder
+	_ "image/jpeg" // Register JPEG decoder
+	_ "image/png"  // Register PNG decoder
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+)
+
+// Hash is a 64-bit perceptual hash (pHash) of an image.
+// Visually similar images have hashes with a small Hamming distance.
+type Hash uint64
+
+const (
+	sampleSize = 32 // Images are reduced to 32x32 grayscale before the DCT
+	hashSize   = 8  // The top-left 8x8 DCT coefficients form the hash
+)
+
+// Distance returns the Hamming distance between two hashes (0-64)
+func (h Hash) Distance(other Hash) int {
+	return bits.OnesCount64(uint64(h ^ other))
+}
+
+// String returns the hash as 16 hex digits
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// MarshalText encodes the hash as hex so snapshots stay readable in JSON
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText decodes a hex-encoded hash
+func (h *Hash) UnmarshalText(text []byte) error {
+	v, err := strconv.ParseUint(string(text), 16, 64)
+	if err != nil {
+		return fmt.Errorf("parse hash: %w", err)
+	}
+	*h = Hash(v)
+	return nil
+}
+
+// HashBytes decodes a PNG, JPEG or GIF image and returns its perceptual hash
+func HashBytes(data []byte) (Hash, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 0, fmt.Errorf("decode image: %w", err)
+	}
+	return HashImage(img), nil
+}
+
+// HashImage computes the perceptual hash of an image
+func HashImage(img image.Image) Hash {
+	pixels := grayscale(img)
+	coeffs := dct2D(pixels)
+
+	// Collect the low-frequency block, skipping the DC term for the median
+	lowFreq := make([]float64, 0, hashSize*hashSize)
+	for y := 0; y < hashSize; y++ {
+		for x := 0; x < hashSize; x++ {
+			lowFreq = append(lowFreq, coeffs[y][x])
+		}
+	}
+	sorted := append([]float64(nil), lowFreq[1:]...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var h Hash
+	for i, c := range lowFreq {
+		if c > median {
+			h |= 1 << uint(i)
+		}
+	}
+	return h
+}
+
+// grayscale downsamples img to sampleSize x sampleSize luminance values
+// by averaging the source pixels that fall into each cell
+func grayscale(img image.Image) [sampleSize][sampleSize]float64 {
+	var out [sampleSize][sampleSize]float64
+	var counts [sampleSize][sampleSize]int
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return out
+	}
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		cy := (y - b.Min.Y) * sampleSize / h
+		for x := b.Min.X; x < b.Max.X; x++ {
+			cx := (x - b.Min.X) * sampleSize / w
+			r, g, bl, _ := img.At(x, y).RGBA()
+			// ITU-R BT.601 luma on 16-bit channels
+			out[cy][cx] += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)
+			counts[cy][cx]++
+		}
+	}
+
+	for y := range out {
+		for x := range out[y] {
+			if counts[y][x] > 0 {
+				out[y][x] /= float64(counts[y][x])
+			}
+		}
+	}
+	return out
+}
+
+// dct2D applies a type-II discrete cosine transform to rows then columns
+func dct2D(in [sampleSize][sampleSize]float64) [sampleSize][sampleSize]float64 {
+	var rows, out [sampleSize][sampleSize]float64
+	for y := 0; y < sampleSize; y++ {
+		rows[y] = dct1D(in[y])
+	}
+	for x := 0; x < sampleSize; x++ {
+		var col [sampleSize]float64
+		for y := 0; y < sampleSize; y++ {
+			col[y] = rows[y][x]
+		}
+		col = dct1D(col)
+		for y := 0; y < sampleSize; y++ {
+			out[y][x] = col[y]
+		}
+	}
+	return out
+}
+
+func dct1D(in [sampleSize]float64) [sampleSize]float64 {
+	var out [sampleSize]float64
+	for k := 0; k < sampleSize; k++ {
+		var sum float64
+		for n := 0; n < sampleSize; n++ {
+			sum += in[n] * math.Cos(math.Pi/sampleSize*(float64(n)+0.5)*float64(k))
+		}
+		out[k] = sum
+	}
+	return out
+}
@@ -0,0 +1,251 @@
+// Package batchexecute encodes and decodes Google Play's batchexecute RPC protocol.
+//
+// A request carries any number of calls in its f.req form field; the response
+// carries one envelope per call, matched back by RPC ID and envelope ID. The
+// package is used by the scraper and the playtest server, and its API follows
+// their needs.
+package batchexecute
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// DefaultID is the envelope ID used for single-call requests
+const DefaultID = "generic"
+
+// ErrNoResult is returned by Get when a response has no envelope for a call
+var ErrNoResult = errors.New("no result for rpc")
+
+// Call is one RPC invocation
+type Call struct {
+	RPCID string
+	Args  any    // Payload; marshalled to JSON on encode, decoded into any by ParseRequest
+	ID    string // Envelope ID echoed back in the response (default DefaultID)
+}
+
+// Result is one decoded response envelope
+type Result struct {
+	RPCID string
+	ID    string
+	Data  json.RawMessage // Inner payload; nil when the call failed
+	Code  int             // Error code reported for a failed call
+}
+
+// Error reports a call that returned no payload
+type Error struct {
+	RPCID string
+	ID    string
+	Code  int
+}
+
+func (e *Error) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("rpc %s failed with code %d", e.RPCID, e.Code)
+	}
+	return fmt.Sprintf("rpc %s returned no data", e.RPCID)
+}
+
+// Batch returns one call per payload, numbering envelope IDs from 1 so the
+// results can be told apart
+func Batch(rpcID string, args ...any) []Call {
+	calls := make([]Call, len(args))
+	for i, a := range args {
+		calls[i] = Call{RPCID: rpcID, Args: a, ID: strconv.Itoa(i + 1)}
+	}
+	return calls
+}
+
+// RPCIDs returns the distinct RPC IDs of calls, comma-separated for the rpcids query parameter
+func RPCIDs(calls ...Call) string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, c := range calls {
+		if !seen[c.RPCID] {
+			seen[c.RPCID] = true
+			ids = append(ids, c.RPCID)
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+// Encode returns the form-encoded request body for calls
+func Encode(calls ...Call) (string, error) {
+	if len(calls) == 0 {
+		return "", fmt.Errorf("no calls to encode")
+	}
+
+	entries := make([]any, len(calls))
+	for i, c := range calls {
+		payload, err := json.Marshal(c.Args)
+		if err != nil {
+			return "", fmt.Errorf("encode %s payload: %w", c.RPCID, err)
+		}
+		id := c.ID
+		if id == "" {
+			id = DefaultID
+		}
+		entries[i] = []any{c.RPCID, string(payload), nil, id}
+	}
+
+	req, err := json.Marshal([]any{entries})
+	if err != nil {
+		return "", fmt.Errorf("encode f.req: %w", err)
+	}
+	return url.Values{"f.req": {string(req)}}.Encode(), nil
+}
+
+// ParseRequest decodes an f.req value into its calls
+func ParseRequest(freq string) ([]Call, error) {
+	var req [][][]any
+	if err := json.Unmarshal([]byte(freq), &req); err != nil {
+		return nil, fmt.Errorf("decode f.req: %w", err)
+	}
+	if len(req) == 0 || len(req[0]) == 0 {
+		return nil, fmt.Errorf("f.req has no calls")
+	}
+
+	calls := make([]Call, 0, len(req[0]))
+	for _, entry := range req[0] {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("malformed call %v", entry)
+		}
+		rpcID, _ := entry[0].(string)
+		payload, _ := entry[1].(string)
+		call := Call{RPCID: rpcID, ID: DefaultID}
+		if len(entry) > 3 {
+			if id, ok := entry[3].(string); ok {
+				call.ID = id
+			}
+		}
+		if err := json.Unmarshal([]byte(payload), &call.Args); err != nil {
+			return nil, fmt.Errorf("decode %s payload: %w", rpcID, err)
+		}
+		calls = append(calls, call)
+	}
+	return calls, nil
+}
+
+// Decode parses a response body. It accepts both the plain format, a single
+// JSON array of envelopes, and the chunked format where each array is preceded
+// by its length. Chunks are framed by their JSON, so the lengths (counted in
+// UTF-16 units) are read and skipped.
+func Decode(body []byte) (*Response, error) {
+	body = bytes.TrimPrefix(body, []byte(")]}'"))
+	dec := json.NewDecoder(bytes.NewReader(body))
+
+	resp := &Response{}
+	chunks := 0
+	for {
+		var chunk json.RawMessage
+		if err := dec.Decode(&chunk); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		if len(chunk) == 0 || chunk[0] != '[' {
+			continue // Length prefix
+		}
+		chunks++
+
+		var envelopes []json.RawMessage
+		if err := json.Unmarshal(chunk, &envelopes); err != nil {
+			return nil, fmt.Errorf("decode response chunk: %w", err)
+		}
+		for _, env := range envelopes {
+			if r, ok := decodeEnvelope(env); ok {
+				resp.Results = append(resp.Results, r)
+			}
+		}
+	}
+	if chunks == 0 {
+		return nil, fmt.Errorf("decode response: no envelopes")
+	}
+	return resp, nil
+}
+
+// decodeEnvelope reads ["wrb.fr", rpcID, data, null, null, [code], id].
+// Other envelope kinds (di, af.httprm, e) carry no results.
+func decodeEnvelope(raw json.RawMessage) (Result, bool) {
+	var fields []any
+	if err := json.Unmarshal(raw, &fields); err != nil || len(fields) < 3 {
+		return Result{}, false
+	}
+	if kind, _ := fields[0].(string); kind != "wrb.fr" {
+		return Result{}, false
+	}
+
+	r := Result{ID: DefaultID}
+	r.RPCID, _ = fields[1].(string)
+	if data, ok := fields[2].(string); ok {
+		r.Data = json.RawMessage(data)
+	}
+	if len(fields) > 5 {
+		if code, ok := fields[5].([]any); ok && len(code) > 0 {
+			if f, ok := code[0].(float64); ok {
+				r.Code = int(f)
+			}
+		}
+	}
+	if len(fields) > 6 {
+		if id, ok := fields[6].(string); ok {
+			r.ID = id
+		}
+	}
+	return r, true
+}
+
+// Response holds the decoded envelopes of a response, in order
+type Response struct {
+	Results []Result
+}
+
+// Get returns the payload for rpcID. An empty id matches any envelope ID.
+// It returns ErrNoResult when no envelope matches and *Error when the call failed.
+func (r *Response) Get(rpcID, id string) (json.RawMessage, error) {
+	for _, res := range r.Results {
+		if res.RPCID != rpcID || (id != "" && res.ID != id) {
+			continue
+		}
+		if res.Data == nil {
+			return nil, &Error{RPCID: res.RPCID, ID: res.ID, Code: res.Code}
+		}
+		return res.Data, nil
+	}
+	return nil, fmt.Errorf("%w %s", ErrNoResult, rpcID)
+}
+
+// WriteResponse writes results in the chunked response format
+func WriteResponse(w io.Writer, results ...Result) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(")]}'\n")
+
+	for _, r := range results {
+		var data any
+		if r.Data != nil {
+			data = string(r.Data)
+		}
+		var code any
+		if r.Code != 0 {
+			code = []int{r.Code}
+		}
+		id := r.ID
+		if id == "" {
+			id = DefaultID
+		}
+		chunk, err := json.Marshal([]any{[]any{"wrb.fr", r.RPCID, data, nil, nil, code, id}})
+		if err != nil {
+			return fmt.Errorf("encode %s envelope: %w", r.RPCID, err)
+		}
+		fmt.Fprintf(bw, "\n%d\n%s", len(utf16.Encode([]rune(string(chunk))))+1, chunk)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,102 @@
+package batchexecute
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	calls := append(Batch("xdSrCf", []any{[]any{nil, []any{"com.a", 7}, []any{}}}, []any{[]any{nil, []any{"com.b", 7}, []any{}}}),
+		Call{RPCID: "IJ4APc", Args: []any{[]any{nil, []any{`say "hi"`}}}})
+
+	body, err := Encode(calls...)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		t.Fatalf("body is not form encoded: %v", err)
+	}
+
+	got, err := ParseRequest(form.Get("f.req"))
+	if err != nil {
+		t.Fatalf("ParseRequest: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d calls, want 3", len(got))
+	}
+	if got[1].RPCID != "xdSrCf" || got[1].ID != "2" || got[2].ID != DefaultID {
+		t.Errorf("calls: %+v", got)
+	}
+	args, _ := json.Marshal(got[2].Args)
+	if string(args) != `[[null,["say \"hi\""]]]` {
+		t.Errorf("args: %s", args)
+	}
+
+	if ids := RPCIDs(calls...); ids != "xdSrCf,IJ4APc" {
+		t.Errorf("RPCIDs = %q", ids)
+	}
+}
+
+func TestDecodePlain(t *testing.T) {
+	body := `)]}'
+
+[["wrb.fr","IJ4APc","[[[[\"maps\"]]]]",null,null,null,"generic"],["di",42],["af.httprm",41,"-1",1]]`
+	resp, err := Decode([]byte(body))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	data, err := resp.Get("IJ4APc", "")
+	if err != nil || string(data) != `[[[["maps"]]]]` {
+		t.Errorf("Get: %s, %v", data, err)
+	}
+	if _, err := resp.Get("xdSrCf", ""); !errors.Is(err, ErrNoResult) {
+		t.Errorf("missing rpc: got %v, want ErrNoResult", err)
+	}
+}
+
+func TestDecodeChunkedRoutesByID(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteResponse(&buf,
+		Result{RPCID: "xdSrCf", ID: "2", Data: json.RawMessage(`["b"]`)},
+		Result{RPCID: "xdSrCf", ID: "1", Data: json.RawMessage(`["a – ünïcode"]`)},
+		Result{RPCID: "xdSrCf", ID: "3", Code: 3},
+	)
+	if err != nil {
+		t.Fatalf("WriteResponse: %v", err)
+	}
+
+	resp, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Decode: %v\n%s", err, buf.String())
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("got %d results", len(resp.Results))
+	}
+	if data, _ := resp.Get("xdSrCf", "1"); string(data) != `["a – ünïcode"]` {
+		t.Errorf("envelope 1: %s", data)
+	}
+	if data, _ := resp.Get("xdSrCf", "2"); string(data) != `["b"]` {
+		t.Errorf("envelope 2: %s", data)
+	}
+
+	_, err = resp.Get("xdSrCf", "3")
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != 3 {
+		t.Errorf("failed call: got %v", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, body := range []string{"", ")]}'\n", "invalid", "\n{invalid"} {
+		if _, err := Decode([]byte(body)); err == nil {
+			t.Errorf("Decode(%q): expected error", body)
+		}
+	}
+	if _, err := Encode(); err == nil {
+		t.Error("Encode with no calls: expected error")
+	}
+}
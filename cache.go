@@ -0,0 +1,309 @@
+package googleplayscraper
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores raw Google Play responses.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// Endpoint identifies the kind of request, for per-endpoint cache TTLs
+type Endpoint string
+
+const (
+	EndpointDetails     Endpoint = "details"     // App details page, also used by Similar
+	EndpointDataSafety  Endpoint = "datasafety"  // Data safety page
+	EndpointDeveloper   Endpoint = "developer"   // Developer page
+	EndpointList        Endpoint = "list"        // Top charts and category pages
+	EndpointCluster     Endpoint = "cluster"     // Cluster pages such as similar apps
+	EndpointSearch      Endpoint = "search"      // Search page and result pagination
+	EndpointReviews     Endpoint = "reviews"     // Reviews RPC
+	EndpointPermissions Endpoint = "permissions" // Permissions RPC
+	EndpointSuggest     Endpoint = "suggest"     // Suggestions RPC
+	EndpointAsset       Endpoint = "asset"       // Images on googleusercontent.com
+	EndpointOther       Endpoint = "other"
+)
+
+// rpcEndpoints maps batchexecute RPC IDs to endpoints
+var rpcEndpoints = map[string]Endpoint{
+	"oCPfdb": EndpointReviews,
+	"UsvDTd": EndpointReviews,
+	"xdSrCf": EndpointPermissions,
+	"IJ4APc": EndpointSuggest,
+	"qnKhOb": EndpointSearch,
+}
+
+// WithCache caches successful responses in cache for ttl.
+// Use WithEndpointTTL to override the TTL for individual endpoints.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// WithEndpointTTL sets the cache TTL for one endpoint; zero disables caching for it
+func WithEndpointTTL(e Endpoint, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if c.endpointTTL == nil {
+			c.endpointTTL = make(map[Endpoint]time.Duration)
+		}
+		c.endpointTTL[e] = ttl
+	}
+}
+
+type bypassCacheKey struct{}
+
+// BypassCache returns a context whose requests skip cached responses.
+// Fresh responses are still stored, so later calls see the refreshed data.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+// cached serves a request from the cache or calls fetch and stores the result
+func (c *Client) cached(ctx context.Context, method, rawURL, body string, fetch func() ([]byte, error)) ([]byte, error) {
+	if c.cache == nil {
+		return fetch()
+	}
+	ttl := c.cacheTTL
+	if endpointTTL, ok := c.endpointTTL[endpointFor(rawURL, body)]; ok {
+		ttl = endpointTTL
+	}
+	if ttl <= 0 {
+		return fetch()
+	}
+
+	key := cacheKey(method, rawURL, body)
+	if !cacheBypassed(ctx) {
+		if data, ok := c.cache.Get(key); ok {
+			return data, nil
+		}
+	}
+
+	data, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Set(key, data, ttl)
+	return data, nil
+}
+
+// cacheKey hashes method, URL and body. Query parameters are sorted so the
+// same request built in a different parameter order shares an entry.
+func cacheKey(method, rawURL, body string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		u.RawQuery = u.Query().Encode()
+		rawURL = u.String()
+	}
+	sum := sha256.Sum256([]byte(method + " " + rawURL + "\n" + body))
+	return hex.EncodeToString(sum[:])
+}
+
+// endpointFor classifies a request by its path, or by RPC ID for batchexecute
+func endpointFor(rawURL, body string) Endpoint {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return EndpointOther
+	}
+	if strings.HasSuffix(u.Hostname(), "googleusercontent.com") {
+		return EndpointAsset
+	}
+
+	path := u.Path
+	switch {
+	case path == "/store/apps/details":
+		return EndpointDetails
+	case path == "/store/apps/datasafety":
+		return EndpointDataSafety
+	case path == "/store/apps/dev", path == "/store/apps/developer":
+		return EndpointDeveloper
+	case path == "/store/apps/top", strings.HasPrefix(path, "/store/apps/category/"):
+		return EndpointList
+	case strings.HasPrefix(path, "/store/apps/collection/"):
+		return EndpointCluster
+	case path == "/store/search":
+		return EndpointSearch
+	case strings.HasSuffix(path, "/batchexecute"):
+		rpcID, _, _ := strings.Cut(u.Query().Get("rpcids"), ",")
+		if rpcID == "" {
+			rpcID = firstRPCID(body)
+		}
+		if e, ok := rpcEndpoints[rpcID]; ok {
+			return e
+		}
+	}
+	return EndpointOther
+}
+
+// firstRPCID reads the first RPC ID from a batchexecute f.req body
+func firstRPCID(body string) string {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return ""
+	}
+	var req [][][]any
+	if err := json.Unmarshal([]byte(values.Get("f.req")), &req); err != nil {
+		return ""
+	}
+	if len(req) == 0 || len(req[0]) == 0 || len(req[0][0]) == 0 {
+		return ""
+	}
+	id, _ := req[0][0][0].(string)
+	return id
+}
+
+// MemoryCache is an in-memory LRU cache
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an LRU cache holding at most maxEntries responses (default 1000)
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// Get returns a cached value that has not expired
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryEntry)
+	if !m.now().Before(entry.expires) {
+		m.ll.Remove(el)
+		delete(m.items, key)
+		return nil, false
+	}
+	m.ll.MoveToFront(el)
+	return entry.value, true
+}
+
+// Set stores value, evicting the least recently used entry when full
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expires := m.now().Add(ttl)
+	if el, ok := m.items[key]; ok {
+		entry := el.Value.(*memoryEntry)
+		entry.value, entry.expires = value, expires
+		m.ll.MoveToFront(el)
+		return
+	}
+
+	m.items[key] = m.ll.PushFront(&memoryEntry{key: key, value: value, expires: expires})
+	for m.ll.Len() > m.maxEntries {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Len returns the number of cached entries, including expired ones not yet evicted
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+// FileCache stores one file per response in a directory, so entries
+// survive restarts and can be shared between processes.
+// Write errors are ignored; they only cost a later cache miss.
+type FileCache struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFileCache creates a cache in dir, creating the directory if needed
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	return &FileCache{dir: dir, now: time.Now}, nil
+}
+
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get returns a cached value that has not expired
+func (f *FileCache) Get(key string) ([]byte, bool) {
+	path := f.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) < 8 {
+		return nil, false
+	}
+	// Files start with the expiry time in Unix nanoseconds
+	expires := time.Unix(0, int64(binary.BigEndian.Uint64(data[:8])))
+	if !f.now().Before(expires) {
+		os.Remove(path)
+		return nil, false
+	}
+	return data[8:], true
+}
+
+// Set writes value atomically, replacing any previous entry
+func (f *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	var header [8]byte
+	binary.BigEndian.PutUint64(header[:], uint64(f.now().Add(ttl).UnixNano()))
+	_, err = tmp.Write(header[:])
+	if err == nil {
+		_, err = tmp.Write(value)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return
+	}
+	os.Rename(tmp.Name(), f.path(key))
+}
@@ -0,0 +1,182 @@
+package googleplayscraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheLRU(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", []byte("1"), time.Minute)
+	c.Set("b", []byte("2"), time.Minute)
+	c.Get("a") // a is now more recently used than b
+	c.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if v, ok := c.Get("a"); !ok || string(v) != "1" {
+		t.Errorf("a: got %q, %v", v, ok)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len: got %d, want 2", c.Len())
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	c := NewMemoryCache(0)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", []byte("1"), time.Minute)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a should be cached")
+	}
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a should have expired")
+	}
+	if c.Len() != 0 {
+		t.Error("expired entry should be removed")
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.Set("key", []byte("page"), time.Hour)
+	if v, ok := c.Get("key"); !ok || string(v) != "page" {
+		t.Errorf("Get: got %q, %v", v, ok)
+	}
+	if _, ok := c.Get("other"); ok {
+		t.Error("unknown key should miss")
+	}
+
+	// A second instance on the same directory sees the entry
+	reopened, _ := NewFileCache(dir)
+	reopened.now = c.now
+	if v, ok := reopened.Get("key"); !ok || string(v) != "page" {
+		t.Errorf("reopened Get: got %q, %v", v, ok)
+	}
+
+	now = now.Add(time.Hour)
+	if _, ok := c.Get("key"); ok {
+		t.Error("entry should have expired")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	a := cacheKey("GET", "https://play.google.com/store/apps/details?id=com.example&hl=en&gl=us", "")
+	b := cacheKey("GET", "https://play.google.com/store/apps/details?gl=us&id=com.example&hl=en", "")
+	if a != b {
+		t.Error("parameter order should not change the key")
+	}
+	if a == cacheKey("POST", "https://play.google.com/store/apps/details?id=com.example&hl=en&gl=us", "") {
+		t.Error("method should change the key")
+	}
+	if cacheKey("POST", "https://play.google.com/x", "a") == cacheKey("POST", "https://play.google.com/x", "b") {
+		t.Error("body should change the key")
+	}
+}
+
+func TestEndpointFor(t *testing.T) {
+	reviewsBody := "f.req=" + url.QueryEscape(`[[["UsvDTd","[null,null,[2,2,[40,null,null]],[\"com.example\",7]]",null,"generic"]]]`)
+	tests := []struct {
+		url  string
+		body string
+		want Endpoint
+	}{
+		{BaseURL + "/store/apps/details?id=com.example", "", EndpointDetails},
+		{BaseURL + "/store/apps/datasafety?id=com.example", "", EndpointDataSafety},
+		{BaseURL + "/store/apps/dev?id=123", "", EndpointDeveloper},
+		{BaseURL + "/store/apps/developer?id=Google", "", EndpointDeveloper},
+		{BaseURL + "/store/apps/top?hl=en", "", EndpointList},
+		{BaseURL + "/store/apps/category/GAME?hl=en", "", EndpointList},
+		{BaseURL + "/store/apps/collection/cluster?gsr=abc", "", EndpointCluster},
+		{BaseURL + "/store/search?q=maps", "", EndpointSearch},
+		{BaseURL + "/_/PlayStoreUi/data/batchexecute?rpcids=xdSrCf", "", EndpointPermissions},
+		{BaseURL + "/_/PlayStoreUi/data/batchexecute?rpcids=IJ4APc", "", EndpointSuggest},
+		{BaseURL + "/_/PlayStoreUi/data/batchexecute?rpcids=oCPfdb&hl=en", "", EndpointReviews},
+		{BaseURL + "/_/PlayStoreUi/data/batchexecute?rpcids=xdSrCf,IJ4APc", "", EndpointPermissions},
+		{BaseURL + "/_/PlayStoreUi/data/batchexecute?hl=en", reviewsBody, EndpointReviews},
+		{"https://play-lh.googleusercontent.com/abc=w240", "", EndpointAsset},
+		{BaseURL + "/about", "", EndpointOther},
+	}
+	for _, tt := range tests {
+		if got := endpointFor(tt.url, tt.body); got != tt.want {
+			t.Errorf("endpointFor(%q): got %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte("page"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := NewClient(
+		WithCache(NewMemoryCache(10), time.Minute),
+		WithEndpointTTL(EndpointDataSafety, 0),
+	)
+	details := server.URL + "/store/apps/details?id=com.example"
+
+	for i := 0; i < 3; i++ {
+		if body, err := c.get(ctx, details); err != nil || string(body) != "page" {
+			t.Fatalf("get: %q, %v", body, err)
+		}
+	}
+	if hits.Load() != 1 {
+		t.Errorf("details should be fetched once, got %d requests", hits.Load())
+	}
+
+	if _, err := c.get(BypassCache(ctx), details); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("BypassCache should refetch, got %d requests", hits.Load())
+	}
+
+	dataSafety := server.URL + "/store/apps/datasafety?id=com.example"
+	c.get(ctx, dataSafety)
+	c.get(ctx, dataSafety)
+	if hits.Load() != 4 {
+		t.Errorf("endpoint with zero TTL should not be cached, got %d requests", hits.Load())
+	}
+
+	c.post(ctx, server.URL+"/_/PlayStoreUi/data/batchexecute?rpcids=IJ4APc", "application/x-www-form-urlencoded", "f.req=a")
+	c.post(ctx, server.URL+"/_/PlayStoreUi/data/batchexecute?rpcids=IJ4APc", "application/x-www-form-urlencoded", "f.req=b")
+	if hits.Load() != 6 {
+		t.Errorf("posts with different bodies should not share an entry, got %d requests", hits.Load())
+	}
+}
+
+func TestClientCacheSkipsErrors(t *testing.T) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithCache(NewMemoryCache(10), time.Minute))
+	c.get(context.Background(), server.URL+"/store/apps/details?id=com.example")
+	c.get(context.Background(), server.URL+"/store/apps/details?id=com.example")
+	if hits.Load() != 2 {
+		t.Errorf("failed responses should not be cached, got %d requests", hits.Load())
+	}
+}
@@ -0,0 +1,49 @@
+// Command gplay-server serves the Google Play scraper as a JSON HTTP API.
+// See package server for the endpoints.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+	"github.com/kryuchenko/google-play-scraper/server"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "listen address")
+	cacheTTL := flag.Duration("cache-ttl", 5*time.Minute, "how long responses are cached (0 disables)")
+	cacheSize := flag.Int("cache-size", 1000, "maximum number of cached responses")
+	timeout := flag.Duration("timeout", 30*time.Second, "HTTP timeout per upstream request")
+	throttle := flag.Duration("throttle", 200*time.Millisecond, "minimum delay between upstream requests")
+	flag.Parse()
+
+	client := gplay.NewClient(gplay.WithTimeout(*timeout), gplay.WithThrottle(*throttle))
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(client, server.Config{CacheTTL: *cacheTTL, MaxCacheEntries: *cacheSize}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("listening on %s", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+// Default table columns per result type
+var (
+	appColumns        = []string{"appId", "title", "developer", "score", "installs", "version", "genre"}
+	resultColumns     = []string{"appId", "title", "developer", "score", "price", "currency"}
+	reviewColumns     = []string{"date", "userName", "score", "thumbsUp", "text"}
+	permissionColumns = []string{"type", "permission"}
+)
+
+var sorts = map[string]gplay.Sort{
+	"newest":      gplay.SortNewest,
+	"rating":      gplay.SortRating,
+	"helpfulness": gplay.SortHelpfulness,
+}
+
+func runApp(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("app", "[flags] <appId>")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	app, err := e.client().App(ctx, fs.Arg(0), gplay.AppOptions{Lang: e.lang, Country: e.country})
+	if app != nil {
+		// Restricted listings come back with an error; print what we have first
+		if werr := e.write(output{Value: app, TableColumns: appColumns}); werr != nil {
+			return werr
+		}
+	}
+	return err
+}
+
+func runReviews(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("reviews", "[flags] <appId>")
+	sortName := fs.String("sort", "newest", "sort order: newest, rating, helpfulness")
+	count := fs.Int("count", 150, "number of reviews (per page unless -all)")
+	token := fs.String("token", "", "pagination token from a previous call")
+	score := fs.Int("score", 0, "only reviews with this score (1-5, 0 = all)")
+	all := fs.Bool("all", false, "follow pagination until -count reviews are fetched")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+	sort, ok := sorts[*sortName]
+	if !ok {
+		return fmt.Errorf("unknown sort %q", *sortName)
+	}
+
+	opts := gplay.ReviewOptions{
+		Lang:        e.lang,
+		Country:     e.country,
+		Sort:        sort,
+		Count:       *count,
+		NextToken:   *token,
+		FilterScore: *score,
+	}
+	client := e.client()
+
+	if *all {
+		reviews, err := client.ReviewsAll(ctx, fs.Arg(0), opts)
+		if err != nil && len(reviews) == 0 {
+			return err
+		}
+		if werr := e.write(output{Value: reviews, TableColumns: reviewColumns}); werr != nil {
+			return werr
+		}
+		return err
+	}
+
+	result, err := client.Reviews(ctx, fs.Arg(0), opts)
+	if err != nil {
+		return err
+	}
+	if e.format != formatJSON && result.NextToken != "" {
+		fmt.Fprintf(e.stderr, "next token: %s\n", result.NextToken)
+	}
+	return e.write(output{Value: result, Rows: result.Reviews, TableColumns: reviewColumns})
+}
+
+func runSearch(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("search", "[flags] <term>")
+	num := fs.Int("num", 20, "number of results")
+	all := fs.Bool("all", false, "follow pagination until results run out, ignoring -num")
+	price := fs.String("price", "all", "price filter: free, paid, all")
+	full := fs.Bool("full", false, "fetch full details for each result")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	results, err := e.client().Search(ctx, gplay.SearchOptions{
+		Term:       fs.Arg(0),
+		Lang:       e.lang,
+		Country:    e.country,
+		Num:        *num,
+		All:        *all,
+		Price:      *price,
+		FullDetail: *full,
+	})
+	if err != nil {
+		return err
+	}
+	return e.write(output{Value: results, TableColumns: resultColumns})
+}
+
+func runList(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("list", "[flags]")
+	collection := fs.String("collection", string(gplay.CollectionTopFree), "collection: TOP_FREE, TOP_PAID, GROSSING")
+	category := fs.String("category", string(gplay.CategoryApplication), "category ID (see gplay categories)")
+	age := fs.String("age", "", "age range: AGE_RANGE1, AGE_RANGE2, AGE_RANGE3")
+	num := fs.Int("num", 50, "number of results")
+	full := fs.Bool("full", false, "fetch full details for each result")
+	if err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	results, err := e.client().List(ctx, gplay.ListOptions{
+		Collection: gplay.Collection(strings.ToUpper(*collection)),
+		Category:   gplay.Category(strings.ToUpper(*category)),
+		Age:        gplay.Age(strings.ToUpper(*age)),
+		Lang:       e.lang,
+		Country:    e.country,
+		Num:        *num,
+		FullDetail: *full,
+	})
+	if err != nil {
+		return err
+	}
+	return e.write(output{Value: results, TableColumns: resultColumns})
+}
+
+func runDeveloper(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("developer", "[flags] <devId>")
+	num := fs.Int("num", 60, "number of results")
+	full := fs.Bool("full", false, "fetch full details for each result")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	results, err := e.client().Developer(ctx, gplay.DeveloperOptions{
+		DevID:      fs.Arg(0),
+		Lang:       e.lang,
+		Country:    e.country,
+		Num:        *num,
+		FullDetail: *full,
+	})
+	if err != nil {
+		return err
+	}
+	return e.write(output{Value: results, TableColumns: resultColumns})
+}
+
+func runSimilar(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("similar", "[flags] <appId>")
+	full := fs.Bool("full", false, "fetch full details for each result")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	results, err := e.client().Similar(ctx, gplay.SimilarOptions{
+		AppID:      fs.Arg(0),
+		Lang:       e.lang,
+		Country:    e.country,
+		FullDetail: *full,
+	})
+	if err != nil {
+		return err
+	}
+	return e.write(output{Value: results, TableColumns: resultColumns})
+}
+
+func runPermissions(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("permissions", "[flags] <appId>")
+	short := fs.Bool("short", false, "return only permission names")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	perms, err := e.client().Permissions(ctx, gplay.PermissionsOptions{
+		AppID:   fs.Arg(0),
+		Lang:    e.lang,
+		Country: e.country,
+		Short:   *short,
+	})
+	if err != nil {
+		return err
+	}
+	return e.write(output{Value: perms, TableColumns: permissionColumns})
+}
+
+// dataSafetyRow flattens the data safety section for line and column formats
+type dataSafetyRow struct {
+	Section  string `json:"section"` // "shared", "collected" or "security"
+	Data     string `json:"data"`
+	Type     string `json:"type"`
+	Purpose  string `json:"purpose"`
+	Optional bool   `json:"optional"`
+}
+
+func dataSafetyRows(ds *gplay.DataSafety) []dataSafetyRow {
+	var rows []dataSafetyRow
+	for _, d := range ds.SharedData {
+		rows = append(rows, dataSafetyRow{"shared", d.Data, d.Type, d.Purpose, d.Optional})
+	}
+	for _, d := range ds.CollectedData {
+		rows = append(rows, dataSafetyRow{"collected", d.Data, d.Type, d.Purpose, d.Optional})
+	}
+	for _, p := range ds.SecurityPractices {
+		rows = append(rows, dataSafetyRow{Section: "security", Data: p.Practice, Purpose: p.Description})
+	}
+	return rows
+}
+
+func runDataSafety(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("datasafety", "[flags] <appId>")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	ds, err := e.client().DataSafety(ctx, gplay.DataSafetyOptions{AppID: fs.Arg(0), Lang: e.lang, Country: e.country})
+	if err != nil {
+		return err
+	}
+	return e.write(output{Value: ds, Rows: dataSafetyRows(ds)})
+}
+
+func runSuggest(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("suggest", "[flags] <term>")
+	if err := e.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	suggestions, err := e.client().Suggest(ctx, gplay.SuggestOptions{Term: fs.Arg(0), Lang: e.lang, Country: e.country})
+	if err != nil {
+		return err
+	}
+	return e.write(output{Value: suggestions})
+}
+
+func runCategories(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("categories", "[flags]")
+	if err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	categories, err := e.client().Categories(ctx, gplay.CategoriesOptions{Lang: e.lang, Country: e.country})
+	if err != nil {
+		return err
+	}
+	return e.write(output{Value: categories})
+}
@@ -0,0 +1,165 @@
+// Command gplay queries Google Play from the command line.
+//
+// Usage:
+//
+//	gplay <command> [flags] [args]
+//
+// Every command accepts -lang, -country, -format (json, jsonl, csv, table),
+// -fields, -timeout and -throttle. Run "gplay <command> -h" for its flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+// errUsage marks errors that were already reported with usage text
+var errUsage = errors.New("usage error")
+
+// env carries the shared flags and output streams of one invocation
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	lang     string
+	country  string
+	format   string
+	fields   string
+	timeout  time.Duration
+	throttle time.Duration
+
+	newClient func(...gplay.ClientOption) *gplay.Client
+}
+
+// flags returns a flag set for the named command with the shared flags registered
+func (e *env) flags(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.lang, "lang", "en", "language code")
+	fs.StringVar(&e.country, "country", "us", "country code")
+	fs.StringVar(&e.format, "format", formatJSON, "output format: "+strings.Join(formats, ", "))
+	fs.StringVar(&e.fields, "fields", "", "comma-separated columns for csv and table output")
+	fs.DurationVar(&e.timeout, "timeout", 30*time.Second, "HTTP timeout per request")
+	fs.DurationVar(&e.throttle, "throttle", 0, "minimum delay between requests")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: gplay %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args and checks the number of positional arguments
+func (e *env) parse(fs *flag.FlagSet, args []string, nargs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() != nargs {
+		fs.Usage()
+		return errUsage
+	}
+	for _, f := range formats {
+		if e.format == f {
+			return nil
+		}
+	}
+	fmt.Fprintf(e.stderr, "unknown format %q (want one of %s)\n", e.format, strings.Join(formats, ", "))
+	return errUsage
+}
+
+func (e *env) client() *gplay.Client {
+	return e.newClient(gplay.WithTimeout(e.timeout), gplay.WithThrottle(e.throttle))
+}
+
+func (e *env) write(out output) error {
+	var columns []string
+	if e.fields != "" {
+		for _, f := range strings.Split(e.fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				columns = append(columns, f)
+			}
+		}
+	}
+	return write(e.stdout, e.format, out, columns)
+}
+
+type command struct {
+	summary string
+	run     func(ctx context.Context, e *env, args []string) error
+}
+
+var commands = map[string]command{
+	"app":         {"Fetch app details", runApp},
+	"reviews":     {"Fetch app reviews", runReviews},
+	"search":      {"Search for apps", runSearch},
+	"list":        {"List apps in a collection", runList},
+	"developer":   {"List apps by a developer", runDeveloper},
+	"similar":     {"List apps similar to an app", runSimilar},
+	"permissions": {"Fetch app permissions", runPermissions},
+	"datasafety":  {"Fetch the data safety section", runDataSafety},
+	"suggest":     {"Fetch search suggestions", runSuggest},
+	"categories":  {"List categories", runCategories},
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: gplay <command> [flags] [args]\n\nCommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(w, "\nRun \"gplay <command> -h\" for command flags.\n")
+}
+
+// run executes one invocation and returns the process exit code
+func run(ctx context.Context, args []string, e *env) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(e.stderr)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "unknown command %q\n\n", args[0])
+		usage(e.stderr)
+		return 2
+	}
+
+	err := cmd.run(ctx, e, args[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	}
+	fmt.Fprintf(e.stderr, "gplay %s: %v\n", args[0], err)
+	return 1
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e := &env{stdout: os.Stdout, stderr: os.Stderr, newClient: gplay.NewClient}
+	os.Exit(run(ctx, os.Args[1:], e))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+	"github.com/kryuchenko/google-play-scraper/playtest"
+)
+
+func testEnv() (*env, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	return &env{stdout: &stdout, stderr: &stderr, newClient: gplay.NewClient}, &stdout, &stderr
+}
+
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"no command", nil, 2},
+		{"help", []string{"help"}, 0},
+		{"unknown command", []string{"nope"}, 2},
+		{"missing argument", []string{"app"}, 2},
+		{"extra argument", []string{"suggest", "a", "b"}, 2},
+		{"bad format", []string{"categories", "-format", "xml"}, 2},
+		{"bad flag", []string{"reviews", "-bogus", "com.example"}, 2},
+		{"command help", []string{"reviews", "-h"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, _, stderr := testEnv()
+			if code := run(context.Background(), tt.args, e); code != tt.code {
+				t.Errorf("exit code: got %d, want %d (stderr: %s)", code, tt.code, stderr)
+			}
+			if stderr.Len() == 0 {
+				t.Error("expected usage on stderr")
+			}
+		})
+	}
+}
+
+func TestRunCategories(t *testing.T) {
+	e, stdout, stderr := testEnv()
+	if code := run(context.Background(), []string{"categories", "-format", "csv"}, e); code != 0 {
+		t.Fatalf("exit code %d: %s", code, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if lines[0] != "value" || len(lines) != len(gplay.AllCategories)+1 {
+		t.Errorf("expected header and one line per category, got %d lines", len(lines))
+	}
+}
+
+func TestRunAgainstPlaytest(t *testing.T) {
+	srv := playtest.NewServer()
+	defer srv.Close()
+	srv.AddApp(gplay.App{AppID: "com.example", Title: "Example Notes", Version: "2.1"})
+	srv.SetPermissions("com.example", gplay.Permission{Type: "Camera", Permission: "take pictures"})
+
+	e, stdout, stderr := testEnv()
+	e.newClient = srv.Client
+	if code := run(context.Background(), []string{"app", "-format", "csv", "-fields", "appId,title,version", "com.example"}, e); code != 0 {
+		t.Fatalf("app: exit code %d: %s", code, stderr)
+	}
+	if want := "appId,title,version\ncom.example,Example Notes,2.1\n"; stdout.String() != want {
+		t.Errorf("app: got %q, want %q", stdout, want)
+	}
+
+	stdout.Reset()
+	if code := run(context.Background(), []string{"permissions", "-format", "csv", "com.example"}, e); code != 0 {
+		t.Fatalf("permissions: exit code %d: %s", code, stderr)
+	}
+	if want := "type,permission\nCamera,take pictures\n"; stdout.String() != want {
+		t.Errorf("permissions: got %q, want %q", stdout, want)
+	}
+}
+
+func TestRunReviewsBadSort(t *testing.T) {
+	e, _, stderr := testEnv()
+	if code := run(context.Background(), []string{"reviews", "-sort", "oldest", "com.example"}, e); code != 1 {
+		t.Errorf("exit code: got %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "unknown sort") {
+		t.Errorf("stderr: %s", stderr)
+	}
+}
+
+func TestDataSafetyRows(t *testing.T) {
+	ds := &gplay.DataSafety{
+		SharedData:        []gplay.DataSafetyEntry{{Data: "Email", Type: "Personal info", Purpose: "Analytics"}},
+		CollectedData:     []gplay.DataSafetyEntry{{Data: "Location", Type: "Location", Optional: true}},
+		SecurityPractices: []gplay.SecurityPractice{{Practice: "Data is encrypted in transit"}},
+	}
+	rows := dataSafetyRows(ds)
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	if rows[0].Section != "shared" || rows[1].Section != "collected" || !rows[1].Optional || rows[2].Section != "security" {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+}
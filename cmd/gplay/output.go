@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+// Output formats
+const (
+	formatJSON  = "json"
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+	formatTable = "table"
+)
+
+var formats = []string{formatJSON, formatJSONL, formatCSV, formatTable}
+
+// output describes what a command prints.
+// JSON prints Value as is; the line and column formats print one entry per
+// element of Rows (or of Value when Rows is nil).
+type output struct {
+	Value        any
+	Rows         any
+	TableColumns []string // Default columns for the table format; CSV defaults to all
+}
+
+// write renders out in the given format. columns, when set, overrides the
+// default column selection for CSV and table output.
+func write(w io.Writer, format string, out output, columns []string) error {
+	if format == formatJSON {
+		data, err := json.MarshalIndent(out.Value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+
+	rows := out.Rows
+	if rows == nil {
+		rows = out.Value
+	}
+	items := flatten(rows)
+
+	switch format {
+	case formatJSONL:
+		enc := json.NewEncoder(w)
+		for _, item := range items {
+			if err := enc.Encode(item.Interface()); err != nil {
+				return fmt.Errorf("encode jsonl: %w", err)
+			}
+		}
+		return nil
+	case formatCSV, formatTable:
+		if len(columns) == 0 && format == formatTable {
+			columns = out.TableColumns
+		}
+		if len(columns) == 0 {
+			columns = allColumns(rows)
+		}
+		if format == formatCSV {
+			return writeCSV(w, items, columns)
+		}
+		return writeTable(w, items, columns)
+	}
+	return fmt.Errorf("unknown format %q (want one of %s)", format, strings.Join(formats, ", "))
+}
+
+// flatten returns the elements of a slice, or v itself for anything else
+func flatten(v any) []reflect.Value {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []reflect.Value{rv}
+	}
+	items := make([]reflect.Value, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i)
+	}
+	return items
+}
+
+// allColumns lists the JSON names of the row type's fields, or "value" for non-struct rows
+func allColumns(v any) []string {
+	t := reflect.TypeOf(v)
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return []string{"value"}
+	}
+
+	var columns []string
+	for i := 0; i < t.NumField(); i++ {
+		// Raw data only makes sense in JSON output
+		if t.Field(i).Type == reflect.TypeOf(&gplay.RawData{}) {
+			continue
+		}
+		if name := jsonName(t.Field(i)); name != "" {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+func jsonName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// cell formats the named column of a row for CSV and table output
+func cell(row reflect.Value, column string) string {
+	for row.Kind() == reflect.Pointer || row.Kind() == reflect.Interface {
+		if row.IsNil() {
+			return ""
+		}
+		row = row.Elem()
+	}
+	if row.Kind() != reflect.Struct {
+		if column == "value" {
+			return formatValue(row)
+		}
+		return ""
+	}
+
+	t := row.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if jsonName(t.Field(i)) == column {
+			return formatValue(row.Field(i))
+		}
+	}
+	return ""
+}
+
+func formatValue(v reflect.Value) string {
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return ""
+		}
+		if v.Type().Elem().Kind() == reflect.String {
+			parts := make([]string, v.Len())
+			for i := range parts {
+				parts[i] = v.Index(i).String()
+			}
+			return strings.Join(parts, "; ")
+		}
+	case reflect.Map, reflect.Pointer:
+		if v.IsNil() {
+			return ""
+		}
+	}
+
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func writeCSV(w io.Writer, items []reflect.Value, columns []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for _, item := range items {
+		for i, column := range columns {
+			record[i] = cell(item, column)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// maxCellWidth keeps long descriptions from blowing up table output
+const maxCellWidth = 60
+
+func writeTable(w io.Writer, items []reflect.Value, columns []string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	cells := make([]string, len(columns))
+	for _, item := range items {
+		for i, column := range columns {
+			cells[i] = truncate(cell(item, column), maxCellWidth)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// truncate shortens s to n runes on a single line
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+var testReviews = []gplay.Review{
+	{ID: "r1", UserName: "Alice", Score: 5, Text: "Great, \"really\"", Date: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+	{ID: "r2", UserName: "Bob", Score: 1, Text: "Crashes\non start"},
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	result := &gplay.ReviewsResult{Reviews: testReviews, NextToken: "tok"}
+	if err := write(&buf, formatJSON, output{Value: result, Rows: result.Reviews}, nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var got gplay.ReviewsResult
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if got.NextToken != "tok" || len(got.Reviews) != 2 {
+		t.Errorf("JSON should carry the full value, got %+v", got)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	result := &gplay.ReviewsResult{Reviews: testReviews, NextToken: "tok"}
+	if err := write(&buf, formatJSONL, output{Value: result, Rows: result.Reviews}, nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per review, got %d", len(lines))
+	}
+	var r gplay.Review
+	if err := json.Unmarshal([]byte(lines[1]), &r); err != nil || r.ID != "r2" {
+		t.Errorf("line 2: got %+v, %v", r, err)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := write(&buf, formatCSV, output{Value: testReviews}, []string{"id", "score", "date", "text"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	want := "id,score,date,text\n" +
+		"r1,5,2026-01-02T03:04:05Z,\"Great, \"\"really\"\"\"\n" +
+		"r2,1,,\"Crashes\non start\"\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteCSVAllColumns(t *testing.T) {
+	var buf bytes.Buffer
+	perms := []gplay.Permission{{Type: "Camera", Permission: "take pictures"}}
+	if err := write(&buf, formatCSV, output{Value: perms}, nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if want := "type,permission\nCamera,take pictures\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	if err := write(&buf, formatTable, output{Value: testReviews, TableColumns: []string{"userName", "score", "text"}}, nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two rows, got %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "USERNAME") {
+		t.Errorf("header: got %q", lines[0])
+	}
+	if !strings.Contains(lines[2], "Crashes on start") {
+		t.Errorf("newlines should be folded in table cells: %q", lines[2])
+	}
+}
+
+func TestWriteScalarRows(t *testing.T) {
+	var buf bytes.Buffer
+	if err := write(&buf, formatCSV, output{Value: []string{"whatsapp", "whatsapp business"}}, nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if want := "value\nwhatsapp\nwhatsapp business\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteSingleStruct(t *testing.T) {
+	var buf bytes.Buffer
+	app := &gplay.App{AppID: "com.example", Title: "Example", Categories: []string{"Tools", "Utilities"}}
+	if err := write(&buf, formatCSV, output{Value: app}, []string{"appId", "title", "categories"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if want := "appId,title,categories\ncom.example,Example,Tools; Utilities\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("abcdef", 4); got != "abc…" {
+		t.Errorf("truncate: got %q", got)
+	}
+	if got := truncate("ab", 4); got != "ab" {
+		t.Errorf("truncate short: got %q", got)
+	}
+}
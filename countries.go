@@ -0,0 +1,116 @@
+package googleplayscraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CountryMatrixOptions configures a multi-country app lookup
+type CountryMatrixOptions struct {
+	Lang              string
+	ReferenceCurrency string             // Convert prices into this currency (e.g. "USD"); empty disables conversion
+	Rates             map[string]float64 // Units of ReferenceCurrency per one unit of each currency code
+}
+
+// CountryEntry holds an app's availability and pricing in one country
+type CountryEntry struct {
+	Country        string       `json:"country"`
+	Available      bool         `json:"available"`
+	Availability   Availability `json:"availability"`
+	Price          float64      `json:"price"`
+	Currency       string       `json:"currency"`
+	PriceText      string       `json:"priceText"`
+	Score          float64      `json:"score"`
+	Installs       string       `json:"installs"`
+	ReferencePrice float64      `json:"referencePrice,omitempty"`
+	Converted      bool         `json:"converted"`       // ReferencePrice is valid
+	Error          string       `json:"error,omitempty"` // Request failure unrelated to availability
+}
+
+// CountryMatrix contains one entry per requested country, in request order
+type CountryMatrix struct {
+	AppID             string         `json:"appId"`
+	ReferenceCurrency string         `json:"referenceCurrency,omitempty"`
+	Entries           []CountryEntry `json:"entries"`
+}
+
+// AppAcrossCountries fetches an app once per country and collects availability,
+// price, score and installs into a matrix. A failure in one country is recorded
+// in its entry and does not stop the others; only context cancellation aborts.
+func (c *Client) AppAcrossCountries(ctx context.Context, appID string, countries []string, opts CountryMatrixOptions) (*CountryMatrix, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("appID is required")
+	}
+	if len(countries) == 0 {
+		return nil, fmt.Errorf("at least one country is required")
+	}
+
+	matrix := &CountryMatrix{
+		AppID:             appID,
+		ReferenceCurrency: strings.ToUpper(opts.ReferenceCurrency),
+		Entries:           make([]CountryEntry, 0, len(countries)),
+	}
+
+	for _, country := range countries {
+		app, err := c.App(ctx, appID, AppOptions{Lang: opts.Lang, Country: country})
+		if err != nil && ctx.Err() != nil {
+			return matrix, ctx.Err()
+		}
+		matrix.Entries = append(matrix.Entries, countryEntry(country, app, err, matrix.ReferenceCurrency, opts.Rates))
+	}
+
+	return matrix, nil
+}
+
+func countryEntry(country string, app *App, err error, refCurrency string, rates map[string]float64) CountryEntry {
+	entry := CountryEntry{Country: country}
+
+	var unavailable *UnavailableError
+	switch {
+	case errors.As(err, &unavailable):
+		entry.Availability = unavailable.Availability
+	case err != nil:
+		entry.Error = err.Error()
+		return entry
+	}
+
+	if app == nil {
+		return entry
+	}
+
+	entry.Available = app.Available
+	if entry.Availability == "" {
+		entry.Availability = app.Availability
+	}
+	entry.Price = app.Price
+	entry.Currency = app.Currency
+	entry.PriceText = app.PriceText
+	entry.Score = app.Score
+	entry.Installs = app.Installs
+
+	if refCurrency != "" {
+		entry.ReferencePrice, entry.Converted = convertPrice(app.Price, app.Currency, refCurrency, rates)
+	}
+
+	return entry
+}
+
+// convertPrice converts price from currency into refCurrency using rates.
+// Free apps convert to zero regardless of currency.
+func convertPrice(price float64, currency, refCurrency string, rates map[string]float64) (float64, bool) {
+	if price == 0 {
+		return 0, true
+	}
+	currency = strings.ToUpper(currency)
+	if currency == refCurrency {
+		return price, true
+	}
+	for code, rate := range rates {
+		if strings.EqualFold(code, currency) {
+			return price * rate, true
+		}
+	}
+	return 0, false
+}
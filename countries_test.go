@@ -0,0 +1,85 @@
+package googleplayscraper
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAppAcrossCountriesValidation(t *testing.T) {
+	c := NewClient()
+
+	if _, err := c.AppAcrossCountries(context.Background(), "", []string{"us"}, CountryMatrixOptions{}); err == nil {
+		t.Error("expected error for empty appID")
+	}
+	if _, err := c.AppAcrossCountries(context.Background(), "com.example", nil, CountryMatrixOptions{}); err == nil {
+		t.Error("expected error for empty country list")
+	}
+}
+
+func TestConvertPrice(t *testing.T) {
+	rates := map[string]float64{"eur": 1.1, "JPY": 0.0067}
+
+	tests := []struct {
+		name     string
+		price    float64
+		currency string
+		want     float64
+		ok       bool
+	}{
+		{"free", 0, "", 0, true},
+		{"same currency", 6.99, "usd", 6.99, true},
+		{"case insensitive rate", 5, "EUR", 5.5, true},
+		{"jpy", 1000, "JPY", 6.7, true},
+		{"missing rate", 3, "GBP", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := convertPrice(tt.price, tt.currency, "USD", rates)
+			if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("convertPrice(%v, %q) = %v, %v; want %v, %v", tt.price, tt.currency, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCountryEntry(t *testing.T) {
+	app := &App{
+		Available:    true,
+		Availability: AvailabilityAvailable,
+		Price:        4.99,
+		Currency:     "EUR",
+		PriceText:    "€4.99",
+		Score:        4.2,
+		Installs:     "1,000+",
+	}
+
+	entry := countryEntry("de", app, nil, "USD", map[string]float64{"EUR": 1.2})
+	if !entry.Available || entry.PriceText != "€4.99" || entry.Installs != "1,000+" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if !entry.Converted || math.Abs(entry.ReferencePrice-5.988) > 1e-9 {
+		t.Errorf("ReferencePrice: got %v (converted=%v)", entry.ReferencePrice, entry.Converted)
+	}
+
+	restricted := &App{Availability: AvailabilityRegionRestricted, Score: 3.9}
+	entry = countryEntry("cn", restricted, &UnavailableError{Availability: AvailabilityRegionRestricted}, "", nil)
+	if entry.Available || entry.Availability != AvailabilityRegionRestricted || entry.Score != 3.9 {
+		t.Errorf("restricted entry: %+v", entry)
+	}
+	if entry.Error != "" {
+		t.Errorf("availability should not be reported as error: %q", entry.Error)
+	}
+
+	entry = countryEntry("xx", nil, &UnavailableError{Availability: AvailabilityNotFound}, "", nil)
+	if entry.Availability != AvailabilityNotFound || entry.Available {
+		t.Errorf("not found entry: %+v", entry)
+	}
+
+	entry = countryEntry("fr", nil, errors.New("timeout"), "", nil)
+	if entry.Error != "timeout" || entry.Availability != "" {
+		t.Errorf("error entry: %+v", entry)
+	}
+}
@@ -0,0 +1,183 @@
+package googleplayscraper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldCoverage counts how a mapped field fared across the records of one response
+type FieldCoverage struct {
+	Field     string
+	Kind      string // Expected JSON type: string, number or array
+	Optional  bool   // Legitimately absent on some records, e.g. Price on free apps
+	Found     int    // Records where the field held a value of the expected type
+	Missing   int    // Records where no candidate path held a value
+	WrongType int    // Records where the value had an unexpected JSON type
+	Got       string // JSON type of the last unexpected value
+	Path      string // Location of the first miss, e.g. ds:5[1][2][79][0][0][0]
+}
+
+// ParseReport describes how well one response matched the field mapping
+// tables. A layout change shows up as required fields no record had, or as
+// values of the wrong type.
+type ParseReport struct {
+	Endpoint Endpoint
+	Source   string // Page URL, or RPC ID for batchexecute responses
+	Records  int    // Apps, results or reviews parsed
+	Fields   []FieldCoverage
+
+	// Mismatches lists the fallbacks the parser took because the response
+	// didn't have the expected structure, such as skipped records
+	Mismatches []ParseError
+
+	loc string // Location of the record being parsed
+}
+
+// Drifted returns the fields that suggest the layout changed: required fields
+// missing from every record, and fields that held a value of the wrong type
+func (r *ParseReport) Drifted() []string {
+	var fields []string
+	for _, f := range r.Fields {
+		if f.drifted() {
+			fields = append(fields, f.Field)
+		}
+	}
+	return fields
+}
+
+func (f FieldCoverage) drifted() bool {
+	return f.WrongType > 0 || (!f.Optional && f.Found == 0 && f.Missing > 0)
+}
+
+// WithParseReport calls fn with a coverage report for every parsed response.
+// fn runs on the calling goroutine before the client method returns.
+func WithParseReport(fn func(ParseReport)) ClientOption {
+	return func(c *Client) {
+		c.parseReport = fn
+	}
+}
+
+// newReport starts a report for a response, or returns nil when neither a
+// report hook nor strict mode needs one
+func (c *Client) newReport(endpoint Endpoint, source string) *ParseReport {
+	if c.parseReport == nil && !c.strict {
+		return nil
+	}
+	return &ParseReport{Endpoint: endpoint, Source: source}
+}
+
+// sendReport delivers a finished report for records parsed results. In
+// strict mode it returns the report's mismatches as an error.
+func (c *Client) sendReport(rep *ParseReport, records int) error {
+	if rep == nil {
+		return nil
+	}
+	rep.Records = records
+	if c.parseReport != nil {
+		c.parseReport(*rep)
+	}
+	if c.strict {
+		return rep.Err()
+	}
+	return nil
+}
+
+// at sets the location of the record about to be parsed
+func (r *ParseReport) at(format string, args ...interface{}) {
+	if r != nil {
+		r.loc = fmt.Sprintf(format, args...)
+	}
+}
+
+// location returns the location of the record being parsed
+func (r *ParseReport) location() string {
+	if r == nil {
+		return ""
+	}
+	return r.loc
+}
+
+// enter moves the location into a field's path and returns the previous
+// location for leave, so nested tables report where they are
+func (r *ParseReport) enter(path []int) string {
+	if r == nil {
+		return ""
+	}
+	prev := r.loc
+	r.loc += pathString(path)
+	return prev
+}
+
+func (r *ParseReport) leave(prev string) {
+	if r != nil {
+		r.loc = prev
+	}
+}
+
+// pathString formats indices as [1][2][0]
+func pathString(path []int) string {
+	var b strings.Builder
+	for _, i := range path {
+		fmt.Fprintf(&b, "[%d]", i)
+	}
+	return b.String()
+}
+
+// fieldStatus is the outcome of looking up one field in one record
+type fieldStatus int
+
+const (
+	fieldFound fieldStatus = iota
+	fieldMissing
+	fieldWrongType
+)
+
+// record counts a lookup outcome at path within the current record; a nil
+// report records nothing
+func (r *ParseReport) record(name string, kind fieldKind, optional bool, status fieldStatus, got interface{}, path []int) {
+	if r == nil {
+		return
+	}
+
+	i := 0
+	for i < len(r.Fields) && r.Fields[i].Field != name {
+		i++
+	}
+	if i == len(r.Fields) {
+		r.Fields = append(r.Fields, FieldCoverage{Field: name, Kind: kind.String(), Optional: optional})
+	}
+
+	f := &r.Fields[i]
+	switch status {
+	case fieldFound:
+		f.Found++
+		return
+	case fieldMissing:
+		f.Missing++
+	case fieldWrongType:
+		f.WrongType++
+		f.Got = jsonType(got)
+	}
+	if f.Path == "" {
+		f.Path = r.loc + pathString(path)
+	}
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "unknown"
+}
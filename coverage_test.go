@@ -0,0 +1,143 @@
+package googleplayscraper
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseReportHook(t *testing.T) {
+	page, err := os.ReadFile("testdata/details_full.html")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	var reports []ParseReport
+	c := NewClient(WithTransport(&fixtureTransport{body: page}), WithParseReport(func(r ParseReport) {
+		reports = append(reports, r)
+	}))
+	if _, err := c.App(context.Background(), "com.anvil.ledger", AppOptions{}); err != nil {
+		t.Fatalf("App: %v", err)
+	}
+
+	if len(reports) != 1 {
+		t.Fatalf("got %d reports, want 1", len(reports))
+	}
+	r := reports[0]
+	if r.Endpoint != EndpointDetails || r.Records != 1 || len(r.Fields) != len(appFields) {
+		t.Errorf("report = %s %d records, %d fields", r.Endpoint, r.Records, len(r.Fields))
+	}
+	if drifted := r.Drifted(); drifted != nil {
+		t.Errorf("complete fixture reported drift in %v", drifted)
+	}
+}
+
+func TestParseReportDrift(t *testing.T) {
+	appData := make([]interface{}, 80)
+	appData[0] = []interface{}{float64(42)}                             // Title became a number
+	appData[13] = []interface{}{"1,000+", float64(1000), float64(1500)} // Installs intact
+	// Genre moved from [79] to [80]
+
+	rep := &ParseReport{}
+	app, err := parseAppPage(appPage(t, appData, ""), "com.example", "url", rep)
+	if err != nil {
+		t.Fatalf("parseAppPage: %v", err)
+	}
+	if app.Installs != "1,000+" {
+		t.Errorf("Installs = %q", app.Installs)
+	}
+
+	fields := make(map[string]FieldCoverage)
+	for _, f := range rep.Fields {
+		fields[f.Field] = f
+	}
+	if f := fields["Title"]; f.WrongType != 1 || f.Got != "number" {
+		t.Errorf("Title coverage = %+v", f)
+	}
+	if f := fields["Genre"]; f.Missing != 1 || f.Found != 0 {
+		t.Errorf("Genre coverage = %+v", f)
+	}
+	if f := fields["Installs"]; f.Found != 1 {
+		t.Errorf("Installs coverage = %+v", f)
+	}
+
+	drifted := make(map[string]bool)
+	for _, name := range rep.Drifted() {
+		drifted[name] = true
+	}
+	if !drifted["Title"] || !drifted["Genre"] || drifted["Installs"] {
+		t.Errorf("Drifted = %v", rep.Drifted())
+	}
+	if drifted["Score"] || drifted["Released"] {
+		t.Errorf("optional fields reported as drift: %v", rep.Drifted())
+	}
+}
+
+func TestParseReportCountsRecords(t *testing.T) {
+	body, err := os.ReadFile("testdata/reviews_rpc.txt")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	rep := &ParseReport{}
+	result, err := parseReviewsResponse(body, "com.anvil.ledger", rep)
+	if err != nil {
+		t.Fatalf("parseReviewsResponse: %v", err)
+	}
+	if len(result.Reviews) != 1 {
+		t.Fatalf("got %d reviews", len(result.Reviews))
+	}
+
+	var names []string
+	for _, f := range rep.Fields {
+		if f.Found != 1 || f.Missing != 0 || f.WrongType != 0 {
+			t.Errorf("%s coverage = %+v", f.Field, f)
+		}
+		names = append(names, f.Field)
+	}
+	var want []string
+	for _, f := range reviewFields {
+		want = append(want, f.name)
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("fields = %v, want %v", names, want)
+	}
+}
+
+func TestParseReportPermissionsAndSuggest(t *testing.T) {
+	perms := rpcEnvelope(t, "xdSrCf", []interface{}{
+		[]interface{}{[]interface{}{"Location", nil, []interface{}{
+			[]interface{}{nil, "precise location"},
+			[]interface{}{nil, "approximate location"},
+		}}},
+	})
+	suggest := rpcEnvelope(t, "IJ4APc", []interface{}{[]interface{}{[]interface{}{
+		[]interface{}{"maps"},
+		[]interface{}{float64(7)}, // Term became a number
+	}}})
+
+	var reports []ParseReport
+	hook := WithParseReport(func(r ParseReport) { reports = append(reports, r) })
+	ctx := context.Background()
+	if _, err := fixtureClient(t, perms, hook).Permissions(ctx, PermissionsOptions{AppID: "com.anvil.ledger"}); err != nil {
+		t.Fatalf("Permissions: %v", err)
+	}
+	if _, err := fixtureClient(t, suggest, hook).Suggest(ctx, SuggestOptions{Term: "ma"}); err != nil {
+		t.Fatalf("Suggest: %v", err)
+	}
+
+	if len(reports) != 2 {
+		t.Fatalf("got %d reports, want 2", len(reports))
+	}
+	if r := reports[0]; r.Endpoint != EndpointPermissions || r.Records != 2 || r.Drifted() != nil {
+		t.Errorf("permissions report = %+v", r)
+	}
+	r := reports[1]
+	if r.Endpoint != EndpointSuggest || r.Records != 1 {
+		t.Errorf("suggest report = %s %d records", r.Endpoint, r.Records)
+	}
+	if f := r.Fields[0]; f.Field != "Suggestion" || f.Found != 1 || f.WrongType != 1 || f.Path != "IJ4APc[0][0][1][0]" {
+		t.Errorf("suggestion coverage = %+v", f)
+	}
+}
@@ -2,9 +2,7 @@ package googleplayscraper
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strings"
 )
 
 // DataSafetyEntry represents a single data collection/sharing entry
@@ -27,6 +25,7 @@ type DataSafety struct {
 	CollectedData     []DataSafetyEntry  `json:"collectedData"`
 	SecurityPractices []SecurityPractice `json:"securityPractices"`
 	PrivacyPolicyURL  string             `json:"privacyPolicyUrl"`
+	Raw               *RawData           `json:"raw,omitempty"` // Set with WithRawData
 }
 
 // DataSafetyOptions configures the data safety request
@@ -57,76 +56,54 @@ func (c *Client) DataSafety(ctx context.Context, opts DataSafetyOptions) (*DataS
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	return parseDataSafetyPage(body)
+	rep := c.newReport(EndpointDataSafety, reqURL)
+	result, err := parseDataSafetyPage(body, rep)
+	if err != nil {
+		return nil, err
+	}
+	records := 0
+	if result != nil {
+		records = 1
+	}
+	if err := c.sendReport(rep, records); err != nil {
+		return nil, err
+	}
+	if result != nil && !c.rawData {
+		result.Raw = nil
+	}
+	return result, nil
 }
 
-func parseDataSafetyPage(body []byte) (*DataSafety, error) {
-	html := string(body)
-
-	// Find data blocks
-	dataBlocks := make(map[string]interface{})
-	matches := scriptDataRegex.FindAllStringSubmatch(html, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 {
-			continue
-		}
-		key := match[1]
-		dataStr := strings.TrimSpace(match[2])
-
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			continue
-		}
-		dataBlocks[key] = data
-	}
+func parseDataSafetyPage(body []byte, rep *ParseReport) (*DataSafety, error) {
+	dataBlocks := parseDataBlocks(body)
 
 	ds3, ok := dataBlocks["ds:3"]
 	if !ok {
+		rep.mismatch("ds:3", "data block not found")
 		return nil, nil
 	}
 
-	result := &DataSafety{}
-
-	// Data safety section: [1][2][1][138]
-	safetySection := getPath(ds3, 1, 2, 1, 138)
+	rep.at("ds:3")
+	page := &dataSafetyPage{rep: rep}
+	page.Raw = &RawData{Blocks: dataBlocks, Node: ds3}
+	applyFields(page, ds3, dataSafetyFields, rep)
 
-	if safetySection != nil {
-		// Shared data: [4][0][0]
-		if sharedData := getPath(safetySection, 4, 0, 0); sharedData != nil {
-			result.SharedData = parseDataEntries(sharedData)
-		}
-
-		// Collected data: [4][1][0]
-		if collectedData := getPath(safetySection, 4, 1, 0); collectedData != nil {
-			result.CollectedData = parseDataEntries(collectedData)
-		}
-
-		// Security practices: [9][2]
-		if practices := getPath(safetySection, 9, 2); practices != nil {
-			result.SecurityPractices = parseSecurityPractices(practices)
-		}
-	}
-
-	// Privacy policy URL: [1][2][1][100][0][5][2]
-	if ppURL := getPath(ds3, 1, 2, 1, 100, 0, 5, 2); ppURL != nil {
-		result.PrivacyPolicyURL = toString(ppURL)
-	}
-
-	return result, nil
+	return &page.DataSafety, nil
 }
 
-func parseDataEntries(data interface{}) []DataSafetyEntry {
+func parseDataEntries(data interface{}, rep *ParseReport) []DataSafetyEntry {
 	arr, ok := data.([]interface{})
 	if !ok {
 		return nil
 	}
 
 	var entries []DataSafetyEntry
+	group := rep.location()
 
-	for _, item := range arr {
+	for i, item := range arr {
 		itemArr, ok := item.([]interface{})
 		if !ok {
+			rep.mismatch(fmt.Sprintf("%s[%d]", group, i), "skipped: not an array")
 			continue
 		}
 
@@ -134,40 +111,21 @@ func parseDataEntries(data interface{}) []DataSafetyEntry {
 		typeName := toString(getPath(itemArr, 0, 1))
 
 		// Data items at [4]
-		dataItems := getPath(itemArr, 4)
-		if dataItems == nil {
-			continue
-		}
-
-		dataItemsArr, ok := dataItems.([]interface{})
+		dataItemsArr, ok := getPath(itemArr, 4).([]interface{})
 		if !ok {
+			rep.mismatch(fmt.Sprintf("%s[%d][4]", group, i), "skipped: data items not found")
 			continue
 		}
 
-		for _, dataItem := range dataItemsArr {
+		for j, dataItem := range dataItemsArr {
 			dataItemArr, ok := dataItem.([]interface{})
 			if !ok {
 				continue
 			}
+			rep.at("%s[%d][4][%d]", group, i, j)
 
-			entry := DataSafetyEntry{
-				Type: typeName,
-			}
-
-			// Data name at [0]
-			if v := getPath(dataItemArr, 0); v != nil {
-				entry.Data = toString(v)
-			}
-
-			// Optional flag at [1] (0 = required, 1 = optional)
-			if v := getPath(dataItemArr, 1); v != nil {
-				entry.Optional = toFloat64(v) == 1
-			}
-
-			// Purpose at [2]
-			if v := getPath(dataItemArr, 2); v != nil {
-				entry.Purpose = toString(v)
-			}
+			entry := DataSafetyEntry{Type: typeName}
+			applyFields(&entry, dataItemArr, dataSafetyEntryFields, rep)
 
 			if entry.Data != "" {
 				entries = append(entries, entry)
@@ -178,31 +136,25 @@ func parseDataEntries(data interface{}) []DataSafetyEntry {
 	return entries
 }
 
-func parseSecurityPractices(data interface{}) []SecurityPractice {
+func parseSecurityPractices(data interface{}, rep *ParseReport) []SecurityPractice {
 	arr, ok := data.([]interface{})
 	if !ok {
 		return nil
 	}
 
 	var practices []SecurityPractice
+	base := rep.location()
 
-	for _, item := range arr {
+	for i, item := range arr {
 		itemArr, ok := item.([]interface{})
 		if !ok || len(itemArr) < 3 {
+			rep.mismatch(fmt.Sprintf("%s[%d]", base, i), "skipped: not a security practice")
 			continue
 		}
+		rep.at("%s[%d]", base, i)
 
 		practice := SecurityPractice{}
-
-		// Practice name at [1]
-		if v := getPath(itemArr, 1); v != nil {
-			practice.Practice = toString(v)
-		}
-
-		// Description at [2][1]
-		if v := getPath(itemArr, 2, 1); v != nil {
-			practice.Description = toString(v)
-		}
+		applyFields(&practice, itemArr, securityPracticeFields, rep)
 
 		if practice.Practice != "" {
 			practices = append(practices, practice)
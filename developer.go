@@ -2,11 +2,9 @@ package googleplayscraper
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/url"
 	"strconv"
-	"strings"
 )
 
 // DeveloperOptions configures the developer apps request
@@ -52,43 +50,30 @@ func (c *Client) Developer(ctx context.Context, opts DeveloperOptions) ([]Search
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	results, err := parseDeveloperPage(body, isNumeric == nil, opts.Num)
+	rep := c.newReport(EndpointDeveloper, devURL)
+	results, err := parseDeveloperPage(body, isNumeric == nil, opts.Num, rep)
 	if err != nil {
 		return nil, err
 	}
+	if err := c.sendReport(rep, len(results)); err != nil {
+		return nil, err
+	}
 
 	// Fetch full details if requested
 	if opts.FullDetail {
 		return c.enrichSearchResults(ctx, results, opts.Lang, opts.Country)
 	}
 
-	return results, nil
+	return c.rawResults(results), nil
 }
 
-func parseDeveloperPage(body []byte, isNumericID bool, num int) ([]SearchResult, error) {
-	html := string(body)
-
-	// Find data blocks
-	dataBlocks := make(map[string]interface{})
-	matches := scriptDataRegex.FindAllStringSubmatch(html, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 {
-			continue
-		}
-		key := match[1]
-		dataStr := strings.TrimSpace(match[2])
-
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			continue
-		}
-		dataBlocks[key] = data
-	}
+func parseDeveloperPage(body []byte, isNumericID bool, num int, rep *ParseReport) ([]SearchResult, error) {
+	dataBlocks := parseDataBlocks(body)
 
 	// Apps are in ds:3
 	ds3, ok := dataBlocks["ds:3"]
 	if !ok {
+		rep.mismatch("ds:3", "data block not found")
 		return nil, nil
 	}
 
@@ -100,20 +85,18 @@ func parseDeveloperPage(body []byte, isNumericID bool, num int) ([]SearchResult,
 		appsPath = []int{0, 1, 0, 22, 0}
 	}
 
-	appsSection := getPath(ds3, appsPath...)
-	if appsSection == nil {
-		return nil, nil
-	}
-
-	apps, ok := appsSection.([]interface{})
+	apps, ok := getPath(ds3, appsPath...).([]interface{})
 	if !ok {
+		rep.mismatch("ds:3"+pathString(appsPath), "apps not found")
 		return nil, nil
 	}
 
 	var results []SearchResult
-	for _, app := range apps {
-		result := parseDeveloperApp(app, isNumericID)
+	for i, app := range apps {
+		rep.at("ds:3%s[%d]", pathString(appsPath), i)
+		result := parseDeveloperApp(app, isNumericID, rep)
 		if result.AppID != "" {
+			result.Raw.Blocks = dataBlocks
 			results = append(results, result)
 		}
 		if len(results) >= num {
@@ -124,73 +107,15 @@ func parseDeveloperPage(body []byte, isNumericID bool, num int) ([]SearchResult,
 	return results, nil
 }
 
-func parseDeveloperApp(item interface{}, isNumericID bool) SearchResult {
-	arr, ok := item.([]interface{})
-	if !ok {
+func parseDeveloperApp(item interface{}, isNumericID bool, rep *ParseReport) SearchResult {
+	if _, ok := item.([]interface{}); !ok {
 		return SearchResult{}
 	}
 
-	result := SearchResult{}
-
-	if isNumericID {
-		// Numeric dev ID format - data directly in arr
-		// appId: [0][0]
-		if v := getPath(arr, 0, 0); v != nil {
-			result.AppID = toString(v)
-		}
-		// title: [3]
-		if v := getPath(arr, 3); v != nil {
-			result.Title = toString(v)
-		}
-		// icon: [1][3][2]
-		if v := getPath(arr, 1, 3, 2); v != nil {
-			result.Icon = toString(v)
-		}
-		// developer: [14]
-		if v := getPath(arr, 14); v != nil {
-			result.Developer = toString(v)
-		}
-		// score: [4][1]
-		if v := getPath(arr, 4, 1); v != nil {
-			result.Score = toFloat64(v)
-		}
-		// scoreText: [4][0]
-		if v := getPath(arr, 4, 0); v != nil {
-			result.ScoreText = toString(v)
-		}
-	} else {
-		// Developer name format - data in arr[0]
-		// appId: [0][0][0]
-		if v := getPath(arr, 0, 0, 0); v != nil {
-			result.AppID = toString(v)
-		}
-		// title: [0][3]
-		if v := getPath(arr, 0, 3); v != nil {
-			result.Title = toString(v)
-		}
-		// icon: [0][1][3][2]
-		if v := getPath(arr, 0, 1, 3, 2); v != nil {
-			result.Icon = toString(v)
-		}
-		// developer: [0][14]
-		if v := getPath(arr, 0, 14); v != nil {
-			result.Developer = toString(v)
-		}
-		// score: [0][4][1]
-		if v := getPath(arr, 0, 4, 1); v != nil {
-			result.Score = toFloat64(v)
-		}
-		// scoreText: [0][4][0]
-		if v := getPath(arr, 0, 4, 0); v != nil {
-			result.ScoreText = toString(v)
-		}
-	}
-
-	result.Free = true
-
-	if result.AppID != "" {
-		result.URL = fmt.Sprintf("%s/store/apps/details?id=%s", BaseURL, result.AppID)
+	// Developer name pages wrap each card: [[card]]
+	card := item
+	if !isNumericID {
+		card = getPath(item, 0)
 	}
-
-	return result
+	return parseCard(card, cardFields, rep)
 }
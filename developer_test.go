@@ -163,7 +163,7 @@ func TestDeveloperOptionsWithFullDetail(t *testing.T) {
 
 func TestParseDeveloperPage(t *testing.T) {
 	// Case 1: Empty or invalid JSON
-	_, err := parseDeveloperPage([]byte("invalid"), false, 10)
+	_, err := parseDeveloperPage([]byte("invalid"), false, 10, nil)
 	if err != nil {
 		// Should handle gracefully or return error depending on implementation
 		// Implementation loops over regex matches, if none, returns nil results, nil error (unless dataBlocks lookup fails)
@@ -175,13 +175,13 @@ func TestParseDeveloperPage(t *testing.T) {
 		<script>AF_initDataCallback({key: 'ds:3', isError: false , hash: '1', data: [[1,[[null,[],null]]]]});</script>
 	`
 	// Path to apps for numeric ID: [0][1][0][21][0]
-	res, err := parseDeveloperPage([]byte(body), true, 10)
+	res, err := parseDeveloperPage([]byte(body), true, 10, nil)
 	if len(res) != 0 {
 		t.Error("expected 0 results for empty apps data")
 	}
 
 	// Let's test missing ds:3
-	res, err = parseDeveloperPage([]byte(`<html></html>`), false, 10)
+	res, err = parseDeveloperPage([]byte(`<html></html>`), false, 10, nil)
 	if len(res) != 0 {
 		t.Error("expected 0 results for missing data")
 	}
@@ -198,7 +198,7 @@ func TestParseDeveloperApp(t *testing.T) {
 		nil,
 		"Title", // [3] = Title
 	}
-	res := parseDeveloperApp(itemNumeric, true)
+	res := parseDeveloperApp(itemNumeric, true, nil)
 	if res.AppID != "app.id" {
 		t.Errorf("expected app.id, got %q", res.AppID)
 	}
@@ -216,7 +216,7 @@ func TestParseDeveloperApp(t *testing.T) {
 			"TitleStr", // [0][3]
 		},
 	}
-	res2 := parseDeveloperApp(itemString, false)
+	res2 := parseDeveloperApp(itemString, false, nil)
 	if res2.AppID != "app.id.str" {
 		t.Errorf("expected app.id.str, got %q", res2.AppID)
 	}
@@ -225,7 +225,7 @@ func TestParseDeveloperApp(t *testing.T) {
 	}
 
 	// Malformed input
-	res3 := parseDeveloperApp("not-an-array", true)
+	res3 := parseDeveloperApp("not-an-array", true, nil)
 	if res3.AppID != "" {
 		t.Error("expected empty result for malformed input")
 	}
@@ -0,0 +1,175 @@
+package googleplayscraper
+
+import (
+	"strings"
+)
+
+// FieldChange is a changed App field; Field uses the JSON field name
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// LineOp marks a line in a text diff
+type LineOp string
+
+const (
+	LineAdded   LineOp = "+"
+	LineRemoved LineOp = "-"
+)
+
+// DiffLine is an added or removed line of a text diff
+type DiffLine struct {
+	Op   LineOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// AppDiff describes the differences between two snapshots of the same app
+type AppDiff struct {
+	AppID                string        `json:"appId"`
+	Changes              []FieldChange `json:"changes"`
+	DescriptionDiff      []DiffLine    `json:"descriptionDiff,omitempty"`
+	ScoreDelta           float64       `json:"scoreDelta"`
+	InstallsJump         bool          `json:"installsJump"` // MinInstalls moved to a higher bucket
+	VersionChanged       bool          `json:"versionChanged"`
+	PrivacyPolicyChanged bool          `json:"privacyPolicyChanged"`
+	ScreenshotsAdded     []string      `json:"screenshotsAdded,omitempty"`
+	ScreenshotsRemoved   []string      `json:"screenshotsRemoved,omitempty"`
+}
+
+// Empty reports whether the snapshots are identical in every compared field
+func (d AppDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Changed reports whether the named field (JSON name, e.g. "version") changed
+func (d AppDiff) Changed(field string) bool {
+	for _, c := range d.Changes {
+		if c.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// diffFields lists the scalar fields compared by DiffApps, by JSON name
+var diffFields = []struct {
+	name string
+	get  func(*App) interface{}
+}{
+	{"title", func(a *App) interface{} { return a.Title }},
+	{"summary", func(a *App) interface{} { return a.Summary }},
+	{"description", func(a *App) interface{} { return a.Description }},
+	{"developer", func(a *App) interface{} { return a.Developer }},
+	{"developerEmail", func(a *App) interface{} { return a.DeveloperEmail }},
+	{"developerWebsite", func(a *App) interface{} { return a.DeveloperWebsite }},
+	{"icon", func(a *App) interface{} { return a.Icon }},
+	{"score", func(a *App) interface{} { return a.Score }},
+	{"ratings", func(a *App) interface{} { return a.Ratings }},
+	{"reviews", func(a *App) interface{} { return a.Reviews }},
+	{"price", func(a *App) interface{} { return a.Price }},
+	{"currency", func(a *App) interface{} { return a.Currency }},
+	{"free", func(a *App) interface{} { return a.Free }},
+	{"installs", func(a *App) interface{} { return a.Installs }},
+	{"minInstalls", func(a *App) interface{} { return a.MinInstalls }},
+	{"genre", func(a *App) interface{} { return a.Genre }},
+	{"version", func(a *App) interface{} { return a.Version }},
+	{"androidVersion", func(a *App) interface{} { return a.AndroidVersion }},
+	{"contentRating", func(a *App) interface{} { return a.ContentRating }},
+	{"updated", func(a *App) interface{} { return a.Updated }},
+	{"video", func(a *App) interface{} { return a.Video }},
+	{"headerImage", func(a *App) interface{} { return a.HeaderImage }},
+	{"privacyPolicy", func(a *App) interface{} { return a.PrivacyPolicy }},
+	{"available", func(a *App) interface{} { return a.Available }},
+	{"availability", func(a *App) interface{} { return a.Availability }},
+}
+
+// DiffApps compares two snapshots of the same app field by field
+func DiffApps(old, new *App) AppDiff {
+	diff := AppDiff{AppID: new.AppID}
+
+	for _, f := range diffFields {
+		o, n := f.get(old), f.get(new)
+		if o != n {
+			diff.Changes = append(diff.Changes, FieldChange{Field: f.name, Old: o, New: n})
+		}
+	}
+
+	diff.ScoreDelta = new.Score - old.Score
+	diff.InstallsJump = new.MinInstalls > old.MinInstalls
+	diff.VersionChanged = diff.Changed("version")
+	diff.PrivacyPolicyChanged = diff.Changed("privacyPolicy")
+
+	if diff.Changed("description") {
+		diff.DescriptionDiff = diffLines(old.Description, new.Description)
+	}
+
+	diff.ScreenshotsAdded = subtract(new.Screenshots, old.Screenshots)
+	diff.ScreenshotsRemoved = subtract(old.Screenshots, new.Screenshots)
+	if len(diff.ScreenshotsAdded) > 0 || len(diff.ScreenshotsRemoved) > 0 {
+		diff.Changes = append(diff.Changes, FieldChange{Field: "screenshots", Old: old.Screenshots, New: new.Screenshots})
+	}
+
+	return diff
+}
+
+// subtract returns items of a that are not in b, preserving order
+func subtract(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	var out []string
+	for _, s := range a {
+		if !inB[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// diffLines returns the added and removed lines between two texts,
+// based on their longest common subsequence of lines
+func diffLines(old, new string) []DiffLine {
+	a := strings.Split(old, "\n")
+	b := strings.Split(new, "\n")
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: LineRemoved, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: LineAdded, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: LineRemoved, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: LineAdded, Text: b[j]})
+	}
+	return lines
+}
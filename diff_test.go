@@ -0,0 +1,100 @@
+package googleplayscraper
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiffAppsIdentical(t *testing.T) {
+	app := &App{AppID: "com.example", Title: "Example", Screenshots: []string{"a", "b"}}
+	copied := *app
+
+	diff := DiffApps(app, &copied)
+	if !diff.Empty() {
+		t.Errorf("expected empty diff, got %+v", diff.Changes)
+	}
+}
+
+func TestDiffApps(t *testing.T) {
+	old := &App{
+		AppID:         "com.example",
+		Title:         "Example",
+		Description:   "Intro\nFeature A\nFeature B",
+		Score:         4.1,
+		Installs:      "1,000,000+",
+		MinInstalls:   1000000,
+		Version:       "1.0",
+		PrivacyPolicy: "https://example.com/privacy",
+		Screenshots:   []string{"s1", "s2", "s3"},
+	}
+	new := &App{
+		AppID:         "com.example",
+		Title:         "Example Pro",
+		Description:   "Intro\nFeature A\nFeature C\nFeature D",
+		Score:         4.3,
+		Installs:      "5,000,000+",
+		MinInstalls:   5000000,
+		Version:       "1.1",
+		PrivacyPolicy: "https://example.com/privacy-v2",
+		Screenshots:   []string{"s2", "s3", "s4"},
+	}
+
+	diff := DiffApps(old, new)
+
+	for _, field := range []string{"title", "description", "score", "installs", "minInstalls", "version", "privacyPolicy", "screenshots"} {
+		if !diff.Changed(field) {
+			t.Errorf("expected %s to be changed", field)
+		}
+	}
+	if diff.Changed("developer") {
+		t.Error("developer should not be changed")
+	}
+
+	if diff.ScoreDelta < 0.199 || diff.ScoreDelta > 0.201 {
+		t.Errorf("ScoreDelta: got %v, want 0.2", diff.ScoreDelta)
+	}
+	if !diff.InstallsJump || !diff.VersionChanged || !diff.PrivacyPolicyChanged {
+		t.Errorf("flags: installs=%v version=%v privacy=%v", diff.InstallsJump, diff.VersionChanged, diff.PrivacyPolicyChanged)
+	}
+	if !reflect.DeepEqual(diff.ScreenshotsAdded, []string{"s4"}) {
+		t.Errorf("ScreenshotsAdded: got %v", diff.ScreenshotsAdded)
+	}
+	if !reflect.DeepEqual(diff.ScreenshotsRemoved, []string{"s1"}) {
+		t.Errorf("ScreenshotsRemoved: got %v", diff.ScreenshotsRemoved)
+	}
+
+	wantLines := []DiffLine{
+		{LineRemoved, "Feature B"},
+		{LineAdded, "Feature C"},
+		{LineAdded, "Feature D"},
+	}
+	if !reflect.DeepEqual(diff.DescriptionDiff, wantLines) {
+		t.Errorf("DescriptionDiff: got %+v, want %+v", diff.DescriptionDiff, wantLines)
+	}
+
+	if _, err := json.Marshal(diff); err != nil {
+		t.Errorf("diff should be JSON serialisable: %v", err)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []DiffLine
+	}{
+		{"equal", "a\nb", "a\nb", nil},
+		{"append", "a", "a\nb", []DiffLine{{LineAdded, "b"}}},
+		{"remove first", "a\nb", "b", []DiffLine{{LineRemoved, "a"}}},
+		{"replace middle", "a\nb\nc", "a\nx\nc", []DiffLine{{LineRemoved, "b"}, {LineAdded, "x"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
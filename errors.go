@@ -0,0 +1,49 @@
+package googleplayscraper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors for apps that cannot be fetched; match them with errors.Is
+var (
+	ErrNotFound         = errors.New("app not found")
+	ErrRegionRestricted = errors.New("app not available in this country")
+	ErrIncompatible     = errors.New("app not compatible with any device")
+	ErrUnavailable      = errors.New("app unavailable")
+)
+
+// StatusError is returned when Google Play responds with a non-200 status
+type StatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status: %d", e.StatusCode)
+}
+
+// UnavailableError reports why an app listing cannot be used.
+// It unwraps to one of ErrNotFound, ErrRegionRestricted, ErrIncompatible or ErrUnavailable.
+type UnavailableError struct {
+	AppID        string
+	Country      string
+	Availability Availability
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("%s (id=%s, country=%s)", e.Unwrap(), e.AppID, e.Country)
+}
+
+func (e *UnavailableError) Unwrap() error {
+	switch e.Availability {
+	case AvailabilityNotFound:
+		return ErrNotFound
+	case AvailabilityRegionRestricted:
+		return ErrRegionRestricted
+	case AvailabilityIncompatible:
+		return ErrIncompatible
+	default:
+		return ErrUnavailable
+	}
+}
@@ -0,0 +1,339 @@
+package googleplayscraper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fieldKind is the JSON type a mapped field is expected to hold
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindNumber
+	kindArray
+)
+
+func (k fieldKind) String() string {
+	switch k {
+	case kindString:
+		return "string"
+	case kindNumber:
+		return "number"
+	case kindArray:
+		return "array"
+	}
+	return "unknown"
+}
+
+// matches reports whether v is a decoded JSON value of kind k
+func (k fieldKind) matches(v interface{}) bool {
+	switch k {
+	case kindString:
+		return isString(v)
+	case kindNumber:
+		return isNumber(v)
+	case kindArray:
+		return isArray(v)
+	}
+	return false
+}
+
+// fieldMap maps one result field to its location in Google Play's data.
+// Candidate paths are tried in order; the first holding a non-nil value
+// accepted by valid (when set) is passed to set. Optional fields are
+// legitimately absent from some records and don't count as drift. check,
+// when set, reports nested values set converts leniently.
+type fieldMap[T any] struct {
+	name     string
+	kind     fieldKind
+	optional bool
+	paths    [][]int
+	valid    func(interface{}) bool
+	set      func(*T, interface{})
+	check    func(interface{}, *ParseReport)
+}
+
+// at lists candidate paths for a field
+func at(paths ...[]int) [][]int {
+	return paths
+}
+
+// lookup returns the value of the first candidate path that holds one
+func (f fieldMap[T]) lookup(data interface{}) (interface{}, bool) {
+	v, _, ok := f.find(data)
+	return v, ok
+}
+
+// find is lookup that also returns the matching path
+func (f fieldMap[T]) find(data interface{}) (interface{}, []int, bool) {
+	for _, path := range f.paths {
+		v := getPath(data, path...)
+		if v == nil || (f.valid != nil && !f.valid(v)) {
+			continue
+		}
+		return v, path, true
+	}
+	return nil, nil, false
+}
+
+// status classifies a lookup for coverage reports and returns the path it
+// concerns. Values that don't match kind are the wrong type; values of the
+// right kind that valid rejected, such as empty strings, count as missing.
+func (f fieldMap[T]) status(data, v interface{}, path []int, ok bool) (fieldStatus, interface{}, []int) {
+	if ok {
+		if !f.kind.matches(v) {
+			return fieldWrongType, v, path
+		}
+		return fieldFound, v, path
+	}
+	for _, path := range f.paths {
+		if v := getPath(data, path...); v != nil && !f.kind.matches(v) {
+			return fieldWrongType, v, path
+		}
+	}
+	return fieldMissing, nil, f.paths[0]
+}
+
+// applyFields fills dst from data using a mapping table, recording each
+// field's outcome in rep when it is non-nil. Setters run with the report
+// located at their field, so nested tables report full paths.
+func applyFields[T any](dst *T, data interface{}, table []fieldMap[T], rep *ParseReport) {
+	for _, f := range table {
+		v, path, ok := f.find(data)
+		if rep != nil {
+			status, got, at := f.status(data, v, path, ok)
+			rep.record(f.name, f.kind, f.optional, status, got, at)
+		}
+		if ok {
+			prev := rep.enter(path)
+			if f.kind == kindNumber {
+				rep.number(f.name, v, nil)
+			}
+			if f.check != nil {
+				f.check(v, rep)
+			}
+			f.set(dst, v)
+			rep.leave(prev)
+		}
+	}
+}
+
+// appFields maps the app block of a details page (ds:5[1][2])
+var appFields = []fieldMap[App]{
+	// [18][0] is 1 for pre-registration and set for installable apps; absent leaves the app unavailable
+	{name: "Availability", kind: kindNumber, optional: true, paths: at([]int{18, 0}), set: func(a *App, v interface{}) {
+		if toInt(v) == 1 {
+			a.Availability = AvailabilityPreRegistration
+			return
+		}
+		a.Availability = AvailabilityAvailable
+		a.Available = true
+	}},
+	{name: "Title", kind: kindString, paths: at([]int{0, 0}), set: func(a *App, v interface{}) { a.Title = toString(v) }},
+	{name: "DescriptionHTML", kind: kindString, paths: at([]int{72, 0, 1}), set: func(a *App, v interface{}) {
+		a.DescriptionHTML = toString(v)
+		a.Description = stripHTML(a.DescriptionHTML)
+	}},
+	{name: "Summary", kind: kindString, paths: at([]int{73, 0, 1}), set: func(a *App, v interface{}) { a.Summary = toString(v) }},
+	{name: "Installs", kind: kindString, paths: at([]int{13, 0}), set: func(a *App, v interface{}) { a.Installs = toString(v) }},
+	{name: "MinInstalls", kind: kindNumber, paths: at([]int{13, 1}), set: func(a *App, v interface{}) { a.MinInstalls = toInt64(v) }},
+	{name: "MaxInstalls", kind: kindNumber, paths: at([]int{13, 2}), set: func(a *App, v interface{}) { a.MaxInstalls = toInt64(v) }},
+	{name: "Score", kind: kindNumber, optional: true, paths: at([]int{51, 0, 1}), set: func(a *App, v interface{}) { a.Score = toFloat64(v) }},
+	{name: "ScoreText", kind: kindString, optional: true, paths: at([]int{51, 0, 0}), set: func(a *App, v interface{}) { a.ScoreText = toString(v) }},
+	{name: "Ratings", kind: kindNumber, optional: true, paths: at([]int{51, 2, 1}), set: func(a *App, v interface{}) { a.Ratings = toInt(v) }},
+	{name: "Reviews", kind: kindNumber, optional: true, paths: at([]int{51, 3, 1}), set: func(a *App, v interface{}) { a.Reviews = toInt(v) }},
+	{name: "Histogram", kind: kindArray, optional: true, paths: at([]int{51, 1}), check: checkHistogram,
+		set: func(a *App, v interface{}) { a.Histogram = extractHistogram(v) }},
+	// Free apps and pre-registration listings may have no price block
+	{name: "Price", kind: kindNumber, optional: true, paths: at([]int{57, 0, 0, 0, 0, 1, 0, 0}), set: func(a *App, v interface{}) {
+		a.Price, a.Free = microsToPrice(v)
+	}},
+	{name: "Currency", kind: kindString, optional: true, paths: at([]int{57, 0, 0, 0, 0, 1, 0, 1}), set: func(a *App, v interface{}) { a.Currency = toString(v) }},
+	{name: "PriceText", kind: kindString, optional: true, paths: at([]int{57, 0, 0, 0, 0, 1, 0, 2}), set: func(a *App, v interface{}) { a.PriceText = toString(v) }},
+	{name: "Developer", kind: kindString, paths: at([]int{68, 0}), set: func(a *App, v interface{}) { a.Developer = toString(v) }},
+	{name: "DeveloperID", kind: kindString, paths: at([]int{68, 1, 4, 2}), set: func(a *App, v interface{}) { a.DeveloperID = toString(v) }},
+	{name: "DeveloperEmail", kind: kindString, optional: true, paths: at([]int{69, 1, 0}), set: func(a *App, v interface{}) { a.DeveloperEmail = toString(v) }},
+	{name: "DeveloperWebsite", kind: kindString, optional: true, paths: at([]int{69, 0, 5, 2}), set: func(a *App, v interface{}) { a.DeveloperWebsite = toString(v) }},
+	{name: "DeveloperAddress", kind: kindString, optional: true, paths: at([]int{69, 2, 0}), set: func(a *App, v interface{}) { a.DeveloperAddress = toString(v) }},
+	{name: "Genre", kind: kindString, paths: at([]int{79, 0, 0, 0}), set: func(a *App, v interface{}) { a.Genre = toString(v) }},
+	{name: "GenreID", kind: kindString, paths: at([]int{79, 0, 0, 2}), set: func(a *App, v interface{}) { a.GenreID = toString(v) }},
+	{name: "Icon", kind: kindString, paths: at([]int{95, 0, 3, 2}), set: func(a *App, v interface{}) { a.Icon = toString(v) }},
+	// Pre-registration listings have no release yet
+	{name: "Version", kind: kindString, optional: true, paths: at([]int{140, 0, 0, 0}), set: func(a *App, v interface{}) { a.Version = toString(v) }},
+	{name: "AndroidVersion", kind: kindString, optional: true, paths: at([]int{140, 1, 1, 0, 0, 1}), set: func(a *App, v interface{}) { a.AndroidVersion = toString(v) }},
+	{name: "ContentRating", kind: kindString, paths: at([]int{9, 0}), set: func(a *App, v interface{}) { a.ContentRating = toString(v) }},
+	{name: "Released", kind: kindString, optional: true, paths: at([]int{10, 1, 0}), set: func(a *App, v interface{}) { a.Released = toString(v) }},
+	// Absent before release, like Version
+	{name: "Updated", kind: kindNumber, optional: true, paths: at([]int{145, 0, 1, 0}), set: func(a *App, v interface{}) { a.Updated = toInt64(v) }},
+	{name: "Screenshots", kind: kindArray, paths: at([]int{78, 0}), set: func(a *App, v interface{}) { a.Screenshots = extractScreenshots(v) }},
+	{name: "HeaderImage", kind: kindString, optional: true, paths: at([]int{96, 0, 3, 2}), set: func(a *App, v interface{}) { a.HeaderImage = toString(v) }},
+	{name: "Video", kind: kindString, optional: true, paths: at([]int{100, 0, 0, 3, 2}), set: func(a *App, v interface{}) { a.Video = toString(v) }},
+	{name: "VideoImage", kind: kindString, optional: true, paths: at([]int{100, 1, 0, 3, 2}), set: func(a *App, v interface{}) { a.VideoImage = toString(v) }},
+	{name: "PrivacyPolicy", kind: kindString, optional: true, paths: at([]int{99, 0, 5, 2}), set: func(a *App, v interface{}) { a.PrivacyPolicy = toString(v) }},
+}
+
+// cardFields maps an app card on list, cluster and developer pages
+var cardFields = []fieldMap[SearchResult]{
+	{name: "AppID", kind: kindString, paths: at([]int{0, 0}), set: func(r *SearchResult, v interface{}) { r.AppID = toString(v) }},
+	{name: "Title", kind: kindString, paths: at([]int{3}), set: func(r *SearchResult, v interface{}) { r.Title = toString(v) }},
+	{name: "Icon", kind: kindString, paths: at([]int{1, 3, 2}), set: func(r *SearchResult, v interface{}) { r.Icon = toString(v) }},
+	{name: "Developer", kind: kindString, paths: at([]int{14}), set: func(r *SearchResult, v interface{}) { r.Developer = toString(v) }},
+	{name: "Score", kind: kindNumber, optional: true, paths: at([]int{4, 1}), set: func(r *SearchResult, v interface{}) { r.Score = toFloat64(v) }},
+	{name: "ScoreText", kind: kindString, optional: true, paths: at([]int{4, 0}), set: func(r *SearchResult, v interface{}) { r.ScoreText = toString(v) }},
+	{name: "Price", kind: kindNumber, optional: true, paths: at([]int{8, 1, 0, 0}), set: func(r *SearchResult, v interface{}) {
+		r.Price, r.Free = microsToPrice(v)
+	}},
+	{name: "Currency", kind: kindString, optional: true, paths: at([]int{8, 1, 0, 1}), set: func(r *SearchResult, v interface{}) { r.Currency = toString(v) }},
+}
+
+// searchCardFields maps a result card on the first search page. Developer
+// pages reuse the parser with the card wrapped one level deeper. Summary,
+// DeveloperID, Installs and Genre are optional until a captured search page
+// with a paid app confirms their paths.
+var searchCardFields = []fieldMap[SearchResult]{
+	{name: "AppID", kind: kindString, paths: at([]int{0, 0, 0, 0}, []int{0, 0, 0}, []int{0, 0}), valid: isAppIDValue,
+		set: func(r *SearchResult, v interface{}) { r.AppID = toString(v) }},
+	{name: "Title", kind: kindString, paths: at([]int{3}), set: func(r *SearchResult, v interface{}) { r.Title = toString(v) }},
+	{name: "Icon", kind: kindString, paths: at([]int{1, 3, 2}, []int{0, 1, 3, 2}), valid: isNonEmpty,
+		set: func(r *SearchResult, v interface{}) { r.Icon = toString(v) }},
+	{name: "Developer", kind: kindString, paths: at([]int{14}), set: func(r *SearchResult, v interface{}) { r.Developer = toString(v) }},
+	{name: "Score", kind: kindNumber, optional: true, paths: at([]int{4, 1}), set: func(r *SearchResult, v interface{}) { r.Score = toFloat64(v) }},
+	{name: "ScoreText", kind: kindString, optional: true, paths: at([]int{4, 0}), set: func(r *SearchResult, v interface{}) { r.ScoreText = toString(v) }},
+	{name: "Price", kind: kindNumber, optional: true, paths: at([]int{8, 1, 0, 0}), set: func(r *SearchResult, v interface{}) {
+		r.Price, r.Free = microsToPrice(v)
+	}},
+	{name: "Currency", kind: kindString, optional: true, paths: at([]int{8, 1, 0, 1}), set: func(r *SearchResult, v interface{}) { r.Currency = toString(v) }},
+	{name: "Summary", kind: kindString, optional: true, paths: at([]int{13, 1}), set: func(r *SearchResult, v interface{}) { r.Summary = toString(v) }},
+	{name: "DeveloperID", kind: kindString, optional: true, paths: at([]int{15, 4, 2}), set: func(r *SearchResult, v interface{}) {
+		if _, id, ok := strings.Cut(toString(v), "?id="); ok {
+			r.DeveloperID = id
+		}
+	}},
+	{name: "Installs", kind: kindString, optional: true, paths: at([]int{6}), set: func(r *SearchResult, v interface{}) { r.Installs = toString(v) }},
+	{name: "Genre", kind: kindString, optional: true, paths: at([]int{5}), set: func(r *SearchResult, v interface{}) { r.Genre = toString(v) }},
+}
+
+// searchRPCFields maps a result of the search pagination RPC (qnKhOb)
+var searchRPCFields = []fieldMap[SearchResult]{
+	{name: "Title", kind: kindString, paths: at([]int{2}), set: func(r *SearchResult, v interface{}) { r.Title = toString(v) }},
+	{name: "AppID", kind: kindString, paths: at([]int{12, 0}), set: func(r *SearchResult, v interface{}) { r.AppID = toString(v) }},
+	{name: "URL", kind: kindString, paths: at([]int{9, 4, 2}), valid: isNonEmpty, set: func(r *SearchResult, v interface{}) { r.URL = BaseURL + toString(v) }},
+	{name: "Icon", kind: kindString, paths: at([]int{1, 1, 0, 3, 2}), set: func(r *SearchResult, v interface{}) { r.Icon = toString(v) }},
+	{name: "Developer", kind: kindString, paths: at([]int{4, 0, 0, 0}), set: func(r *SearchResult, v interface{}) { r.Developer = toString(v) }},
+	{name: "DeveloperID", kind: kindString, paths: at([]int{4, 0, 0, 1, 4, 2}), set: func(r *SearchResult, v interface{}) {
+		if _, id, ok := strings.Cut(toString(v), "?id="); ok {
+			r.DeveloperID = id
+		}
+	}},
+	{name: "Currency", kind: kindString, optional: true, paths: at([]int{7, 0, 3, 2, 1, 0, 1}), set: func(r *SearchResult, v interface{}) { r.Currency = toString(v) }},
+	{name: "Price", kind: kindNumber, optional: true, paths: at([]int{7, 0, 3, 2, 1, 0, 0}), set: func(r *SearchResult, v interface{}) {
+		r.Price, r.Free = microsToPrice(v)
+	}},
+	{name: "Summary", kind: kindString, paths: at([]int{4, 1, 1, 1, 1}), set: func(r *SearchResult, v interface{}) { r.Summary = toString(v) }},
+	{name: "ScoreText", kind: kindString, optional: true, paths: at([]int{6, 0, 2, 1, 0}), set: func(r *SearchResult, v interface{}) { r.ScoreText = toString(v) }},
+	{name: "Score", kind: kindNumber, optional: true, paths: at([]int{6, 0, 2, 1, 1}), set: func(r *SearchResult, v interface{}) { r.Score = toFloat64(v) }},
+}
+
+// reviewFields maps a review from the reviews RPC (oCPfdb)
+var reviewFields = []fieldMap[Review]{
+	{name: "ID", kind: kindString, paths: at([]int{0}), valid: isString, set: func(r *Review, v interface{}) { r.ID = v.(string) }},
+	{name: "UserName", kind: kindString, paths: at([]int{1, 0}), valid: isString, set: func(r *Review, v interface{}) { r.UserName = v.(string) }},
+	{name: "UserImage", kind: kindString, paths: at([]int{1, 1, 3, 2}), valid: isString, set: func(r *Review, v interface{}) { r.UserImage = v.(string) }},
+	{name: "Score", kind: kindNumber, paths: at([]int{2}), valid: isNumber, set: func(r *Review, v interface{}) { r.Score = toInt(v) }},
+	{name: "Text", kind: kindString, paths: at([]int{4}), valid: isString, set: func(r *Review, v interface{}) { r.Text = v.(string) }},
+	{name: "Date", kind: kindArray, paths: at([]int{5}), valid: isArray, set: func(r *Review, v interface{}) { r.Date = parseTimestamp(v.([]interface{})) }},
+	{name: "ThumbsUp", kind: kindNumber, paths: at([]int{6}), valid: isNumber, set: func(r *Review, v interface{}) { r.ThumbsUp = toInt(v) }},
+	{name: "ReplyText", kind: kindString, optional: true, paths: at([]int{7, 1}), valid: isString, set: func(r *Review, v interface{}) { r.ReplyText = v.(string) }},
+	{name: "ReplyDate", kind: kindArray, optional: true, paths: at([]int{7, 2}), valid: isArray, set: func(r *Review, v interface{}) { r.ReplyDate = parseTimestamp(v.([]interface{})) }},
+	{name: "Version", kind: kindString, optional: true, paths: at([]int{10}), valid: isString, set: func(r *Review, v interface{}) { r.Version = v.(string) }},
+}
+
+// dataSafetyPage is the target of dataSafetyFields. It carries the report so
+// the entry tables applied by the group setters are reported too.
+type dataSafetyPage struct {
+	DataSafety
+	rep *ParseReport
+}
+
+// dataSafetyFields maps the data safety page (ds:3)
+var dataSafetyFields = []fieldMap[dataSafetyPage]{
+	{name: "SharedData", kind: kindArray, optional: true, paths: at([]int{1, 2, 1, 138, 4, 0, 0}), set: func(d *dataSafetyPage, v interface{}) {
+		d.SharedData = parseDataEntries(v, d.rep)
+	}},
+	{name: "CollectedData", kind: kindArray, optional: true, paths: at([]int{1, 2, 1, 138, 4, 1, 0}), set: func(d *dataSafetyPage, v interface{}) {
+		d.CollectedData = parseDataEntries(v, d.rep)
+	}},
+	{name: "SecurityPractices", kind: kindArray, optional: true, paths: at([]int{1, 2, 1, 138, 9, 2}), set: func(d *dataSafetyPage, v interface{}) {
+		d.SecurityPractices = parseSecurityPractices(v, d.rep)
+	}},
+	{name: "PrivacyPolicyURL", kind: kindString, paths: at([]int{1, 2, 1, 100, 0, 5, 2}), set: func(d *dataSafetyPage, v interface{}) { d.PrivacyPolicyURL = toString(v) }},
+}
+
+// dataSafetyEntryFields maps one item of a data safety group: [data, optional, purpose]
+var dataSafetyEntryFields = []fieldMap[DataSafetyEntry]{
+	{name: "Data", kind: kindString, paths: at([]int{0}), set: func(e *DataSafetyEntry, v interface{}) { e.Data = toString(v) }},
+	{name: "Optional", kind: kindNumber, paths: at([]int{1}), set: func(e *DataSafetyEntry, v interface{}) { e.Optional = toFloat64(v) == 1 }},
+	{name: "Purpose", kind: kindString, paths: at([]int{2}), set: func(e *DataSafetyEntry, v interface{}) { e.Purpose = toString(v) }},
+}
+
+// securityPracticeFields maps one security practice: [null, practice, [null, description]]
+var securityPracticeFields = []fieldMap[SecurityPractice]{
+	{name: "Practice", kind: kindString, paths: at([]int{1}), set: func(p *SecurityPractice, v interface{}) { p.Practice = toString(v) }},
+	{name: "Description", kind: kindString, paths: at([]int{2, 1}), set: func(p *SecurityPractice, v interface{}) { p.Description = toString(v) }},
+}
+
+// permissionFields maps one permission of a group: [icon, permission]
+var permissionFields = []fieldMap[Permission]{
+	{name: "Permission", kind: kindString, paths: at([]int{1}), set: func(p *Permission, v interface{}) { p.Permission = toString(v) }},
+}
+
+// suggestionFields maps one entry of the suggestions RPC (IJ4APc): [term]
+var suggestionFields = []fieldMap[string]{
+	{name: "Suggestion", kind: kindString, paths: at([]int{0}), valid: isString, set: func(s *string, v interface{}) { *s = v.(string) }},
+}
+
+// parseCard maps an app card with table. Cards without a price are free.
+func parseCard(card interface{}, table []fieldMap[SearchResult], rep *ParseReport) SearchResult {
+	result := SearchResult{Free: true, Raw: &RawData{Node: card}}
+	applyFields(&result, card, table, rep)
+	if result.AppID != "" && result.URL == "" {
+		result.URL = detailsURL(result.AppID)
+	}
+	return result
+}
+
+// microsToPrice converts a price in millionths to a price and free flag
+func microsToPrice(v interface{}) (float64, bool) {
+	micros := toFloat64(v)
+	return micros / 1000000, micros == 0
+}
+
+func isString(v interface{}) bool {
+	_, ok := v.(string)
+	return ok
+}
+
+func isNumber(v interface{}) bool {
+	_, ok := v.(float64)
+	return ok
+}
+
+func isArray(v interface{}) bool {
+	_, ok := v.([]interface{})
+	return ok
+}
+
+func isNonEmpty(v interface{}) bool {
+	return toString(v) != ""
+}
+
+func isAppIDValue(v interface{}) bool {
+	id, ok := v.(string)
+	return ok && ValidAppID(id)
+}
+
+// detailsURL is the details page link for an app
+func detailsURL(appID string) string {
+	return fmt.Sprintf("%s/store/apps/details?id=%s", BaseURL, appID)
+}
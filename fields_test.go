@@ -0,0 +1,111 @@
+package googleplayscraper
+
+import (
+	"os"
+	"testing"
+)
+
+// fixtureBlocks decodes the data blocks of a testdata page
+func fixtureBlocks(t *testing.T, name string) map[string]interface{} {
+	t.Helper()
+	page, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return parseDataBlocks(page)
+}
+
+// fixtureRPC decodes the payload of a testdata batchexecute response
+func fixtureRPC(t *testing.T, name, rpcID string) interface{} {
+	t.Helper()
+	body, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	data, err := rpcData(body, rpcID)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return data
+}
+
+// checkFields asserts that every field in table is found, with its declared
+// kind, in at least one of nodes
+func checkFields[T any](t *testing.T, table []fieldMap[T], nodes ...interface{}) {
+	t.Helper()
+	if len(nodes) == 0 {
+		t.Fatal("no fixture nodes")
+	}
+	for _, f := range table {
+		found := false
+		for _, node := range nodes {
+			if v, ok := f.lookup(node); ok {
+				if !f.kind.matches(v) {
+					t.Errorf("%s: got %T, want kind %d", f.name, v, f.kind)
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("%s: not found at any of %v", f.name, f.paths)
+		}
+	}
+}
+
+func TestAppFieldsCovered(t *testing.T) {
+	var nodes []interface{}
+	for _, name := range []string{"details_full.html", "preregister.html"} {
+		nodes = append(nodes, getPath(fixtureBlocks(t, name)["ds:5"], 1, 2))
+	}
+	checkFields(t, appFields, nodes...)
+}
+
+func TestCardFieldsCovered(t *testing.T) {
+	cards, _ := getPath(fixtureBlocks(t, "top_charts.html")["ds:4"], 0, 1, 1, 21, 0).([]interface{})
+	checkFields(t, cardFields, cards...)
+}
+
+func TestSearchCardFieldsCovered(t *testing.T) {
+	cards, _ := getPath(fixtureBlocks(t, "search.html")["ds:4"], 0, 1, 0, 0, 0).([]interface{})
+	checkFields(t, searchCardFields, cards...)
+}
+
+func TestSearchRPCFieldsCovered(t *testing.T) {
+	results, _ := getPath(fixtureRPC(t, "search_rpc.txt", "qnKhOb"), 0, 0, 0).([]interface{})
+	checkFields(t, searchRPCFields, results...)
+}
+
+func TestReviewFieldsCovered(t *testing.T) {
+	reviews, _ := getPath(fixtureRPC(t, "reviews_rpc.txt", "oCPfdb"), 0).([]interface{})
+	checkFields(t, reviewFields, reviews...)
+}
+
+func TestDataSafetyFieldsCovered(t *testing.T) {
+	data := fixtureBlocks(t, "datasafety.html")["ds:3"]
+	checkFields(t, dataSafetyFields, data)
+
+	var entries []interface{}
+	for _, group := range []int{0, 1} {
+		items, _ := getPath(data, 1, 2, 1, 138, 4, group, 0).([]interface{})
+		for _, item := range items {
+			if dataItems, ok := getPath(item, 4).([]interface{}); ok {
+				entries = append(entries, dataItems...)
+			}
+		}
+	}
+	checkFields(t, dataSafetyEntryFields, entries...)
+
+	practices, _ := getPath(data, 1, 2, 1, 138, 9, 2).([]interface{})
+	checkFields(t, securityPracticeFields, practices...)
+}
+
+func TestParseCardDefaults(t *testing.T) {
+	r := parseCard([]interface{}{[]interface{}{"com.example.app", 7.0}}, cardFields, nil)
+	if !r.Free || r.URL != detailsURL("com.example.app") {
+		t.Errorf("parseCard = %+v", r)
+	}
+	if r := parseCard(nil, cardFields, nil); r.URL != "" {
+		t.Errorf("empty card got URL %q", r.URL)
+	}
+}
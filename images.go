@@ -0,0 +1,148 @@
+package googleplayscraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImageFormat selects the encoding returned by Google's image server
+type ImageFormat string
+
+const (
+	ImageFormatDefault ImageFormat = ""   // Server decides (usually WebP)
+	ImageFormatJPEG    ImageFormat = "rj" // Force JPEG
+	ImageFormatPNG     ImageFormat = "rp" // Force PNG
+	ImageFormatWebP    ImageFormat = "rw" // Force WebP
+)
+
+// ImageSize configures a rewritten image URL
+type ImageSize struct {
+	Width  int // Target width in pixels (0 = keep aspect ratio)
+	Height int // Target height in pixels (0 = keep aspect ratio)
+	Format ImageFormat
+}
+
+// Asset is a store asset saved to disk
+type Asset struct {
+	URL         string `json:"url"`
+	Path        string `json:"path"`
+	Hash        string `json:"hash"` // Hex-encoded SHA-256 of the content
+	ContentType string `json:"contentType"`
+	Size        int    `json:"size"`
+}
+
+// ImageURL rewrites a googleusercontent.com image URL for the given size and format.
+// Zero Width and Height request the original upload (=s0).
+// URLs from other hosts are returned unchanged.
+func ImageURL(rawURL string, size ImageSize) string {
+	if !strings.Contains(rawURL, "googleusercontent.com/") {
+		return rawURL
+	}
+
+	// Drop any existing size suffix: everything after "=" in the last path segment
+	base := rawURL
+	if slash := strings.LastIndex(base, "/"); slash >= 0 {
+		if eq := strings.Index(base[slash:], "="); eq >= 0 {
+			base = base[:slash+eq]
+		}
+	}
+
+	var params []string
+	if size.Width > 0 {
+		params = append(params, fmt.Sprintf("w%d", size.Width))
+	}
+	if size.Height > 0 {
+		params = append(params, fmt.Sprintf("h%d", size.Height))
+	}
+	if len(params) == 0 {
+		params = append(params, "s0")
+	}
+	if size.Format != ImageFormatDefault {
+		params = append(params, string(size.Format))
+	}
+
+	return base + "=" + strings.Join(params, "-")
+}
+
+// FetchAsset downloads a store asset using the client's throttling and transport.
+// It returns the content and its sniffed MIME type.
+func (c *Client) FetchAsset(ctx context.Context, rawURL string) ([]byte, string, error) {
+	if rawURL == "" {
+		return nil, "", fmt.Errorf("asset URL is required")
+	}
+	if strings.HasPrefix(rawURL, "//") {
+		rawURL = "https:" + rawURL
+	}
+
+	body, err := c.get(ctx, rawURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("request failed: %w", err)
+	}
+
+	return body, http.DetectContentType(body), nil
+}
+
+// DownloadAsset fetches a store asset and writes it to dir, named by its content hash.
+// Identical content is stored once, so re-downloading an unchanged asset is a no-op on disk.
+func (c *Client) DownloadAsset(ctx context.Context, rawURL, dir string) (*Asset, error) {
+	body, contentType, err := c.FetchAsset(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	path := filepath.Join(dir, hash+assetExtension(contentType))
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.WriteFile(path, body, 0644); err != nil {
+			return nil, fmt.Errorf("write asset: %w", err)
+		}
+	}
+
+	return &Asset{
+		URL:         rawURL,
+		Path:        path,
+		Hash:        hash,
+		ContentType: contentType,
+		Size:        len(body),
+	}, nil
+}
+
+// DownloadAssets downloads each URL into dir in order.
+// On error it returns the assets saved so far along with the error.
+func (c *Client) DownloadAssets(ctx context.Context, urls []string, dir string) ([]Asset, error) {
+	assets := make([]Asset, 0, len(urls))
+	for _, u := range urls {
+		asset, err := c.DownloadAsset(ctx, u, dir)
+		if err != nil {
+			return assets, fmt.Errorf("download %s: %w", u, err)
+		}
+		assets = append(assets, *asset)
+	}
+	return assets, nil
+}
+
+func assetExtension(contentType string) string {
+	switch contentType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ".bin"
+	}
+}
@@ -0,0 +1,92 @@
+package googleplayscraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImageURL(t *testing.T) {
+	const base = "https://play-lh.googleusercontent.com/abc123"
+
+	tests := []struct {
+		name  string
+		input string
+		size  ImageSize
+		want  string
+	}{
+		{"original", base, ImageSize{}, base + "=s0"},
+		{"width only", base, ImageSize{Width: 512}, base + "=w512"},
+		{"width and height", base, ImageSize{Width: 512, Height: 256}, base + "=w512-h256"},
+		{"replace suffix", base + "=w526-h296-rw", ImageSize{Width: 100}, base + "=w100"},
+		{"force jpeg", base + "=s64", ImageSize{Format: ImageFormatJPEG}, base + "=s0-rj"},
+		{"protocol relative", "//lh3.googleusercontent.com/x=w10", ImageSize{Height: 20}, "//lh3.googleusercontent.com/x=h20"},
+		{"other host", "https://example.com/a.png?x=1", ImageSize{Width: 10}, "https://example.com/a.png?x=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ImageURL(tt.input, tt.size); got != tt.want {
+				t.Errorf("ImageURL(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadAssets(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(png)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	c := NewClient()
+
+	assets, err := c.DownloadAssets(context.Background(), []string{server.URL + "/a", server.URL + "/b"}, dir)
+	if err != nil {
+		t.Fatalf("DownloadAssets failed: %v", err)
+	}
+	if len(assets) != 2 {
+		t.Fatalf("expected 2 assets, got %d", len(assets))
+	}
+	if assets[0].ContentType != "image/png" {
+		t.Errorf("ContentType: got %q, want image/png", assets[0].ContentType)
+	}
+	if assets[0].Path != assets[1].Path {
+		t.Error("identical content should share a path")
+	}
+	if filepath.Ext(assets[0].Path) != ".png" {
+		t.Errorf("unexpected extension: %s", assets[0].Path)
+	}
+
+	data, err := os.ReadFile(assets[0].Path)
+	if err != nil {
+		t.Fatalf("read asset: %v", err)
+	}
+	if string(data) != string(png) {
+		t.Error("asset content mismatch")
+	}
+
+	assets, err = c.DownloadAssets(context.Background(), []string{server.URL + "/a", server.URL + "/missing"}, dir)
+	if err == nil {
+		t.Error("expected error for missing asset")
+	}
+	if len(assets) != 1 {
+		t.Errorf("expected partial result of 1 asset, got %d", len(assets))
+	}
+}
+
+func TestFetchAssetValidation(t *testing.T) {
+	c := NewClient()
+	if _, _, err := c.FetchAsset(context.Background(), ""); err == nil {
+		t.Error("expected error for empty URL")
+	}
+}
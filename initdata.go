@@ -0,0 +1,200 @@
+package googleplayscraper
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// initDataBlock is one AF_initDataCallback({key: 'ds:N', hash: '…', data: …}) call in a page
+type initDataBlock struct {
+	Key  string
+	Hash string
+	Data []byte // Raw JSON, sliced from the page body
+}
+
+var initDataMarker = []byte("AF_initDataCallback(")
+
+// extractInitData scans a page for AF_initDataCallback calls and returns their
+// key, hash and raw data in page order. The argument object is tokenized, so
+// field order and the contents of strings don't matter. Calls that can't be
+// tokenized are skipped.
+func extractInitData(body []byte) []initDataBlock {
+	var blocks []initDataBlock
+	for pos := 0; ; {
+		i := bytes.Index(body[pos:], initDataMarker)
+		if i < 0 {
+			return blocks
+		}
+		pos += i + len(initDataMarker)
+
+		block, end, ok := parseInitDataObject(body, pos)
+		if !ok {
+			continue
+		}
+		pos = end
+		if block.Key != "" && block.Data != nil {
+			blocks = append(blocks, block)
+		}
+	}
+}
+
+// parseInitDataObject reads the object literal starting at pos and returns the
+// offset just past it
+func parseInitDataObject(body []byte, pos int) (initDataBlock, int, bool) {
+	var block initDataBlock
+
+	pos = skipSpace(body, pos)
+	if pos >= len(body) || body[pos] != '{' {
+		return block, pos, false
+	}
+	pos++
+
+	for {
+		pos = skipSpace(body, pos)
+		if pos >= len(body) {
+			return block, pos, false
+		}
+		if body[pos] == '}' {
+			return block, pos + 1, true
+		}
+
+		name, next, ok := scanName(body, pos)
+		if !ok {
+			return block, pos, false
+		}
+		pos = skipSpace(body, next)
+		if pos >= len(body) || body[pos] != ':' {
+			return block, pos, false
+		}
+		pos = skipSpace(body, pos+1)
+
+		end, ok := scanValue(body, pos)
+		if !ok {
+			return block, pos, false
+		}
+		value := body[pos:end]
+		switch name {
+		case "key":
+			block.Key = unquoteJS(value)
+		case "hash":
+			block.Hash = unquoteJS(value)
+		case "data":
+			block.Data = value
+		}
+
+		pos = skipSpace(body, end)
+		if pos < len(body) && body[pos] == ',' {
+			pos++
+		}
+	}
+}
+
+func skipSpace(body []byte, pos int) int {
+	for pos < len(body) {
+		switch body[pos] {
+		case ' ', '\t', '\n', '\r':
+			pos++
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+// scanName reads a bare or quoted property name
+func scanName(body []byte, pos int) (string, int, bool) {
+	if body[pos] == '\'' || body[pos] == '"' {
+		end, ok := scanString(body, pos)
+		if !ok {
+			return "", pos, false
+		}
+		return unquoteJS(body[pos:end]), end, true
+	}
+
+	start := pos
+	for pos < len(body) && isNameByte(body[pos]) {
+		pos++
+	}
+	return string(body[start:pos]), pos, pos > start
+}
+
+func isNameByte(b byte) bool {
+	return b == '_' || b == '$' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// scanString returns the offset just past the quoted string at pos
+func scanString(body []byte, pos int) (int, bool) {
+	quote := body[pos]
+	for i := pos + 1; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1, true
+		}
+	}
+	return len(body), false
+}
+
+// scanValue returns the offset just past the value at pos: a string, a
+// bracketed array or object, or a bare literal such as true or 42
+func scanValue(body []byte, pos int) (int, bool) {
+	if pos >= len(body) {
+		return pos, false
+	}
+
+	switch body[pos] {
+	case '\'', '"':
+		return scanString(body, pos)
+	case '[', '{':
+		depth := 0
+		for i := pos; i < len(body); i++ {
+			switch body[i] {
+			case '\'', '"':
+				end, ok := scanString(body, i)
+				if !ok {
+					return end, false
+				}
+				i = end - 1
+			case '[', '{':
+				depth++
+			case ']', '}':
+				depth--
+				if depth == 0 {
+					return i + 1, true
+				}
+			}
+		}
+		return len(body), false
+	}
+
+	end := pos
+	for end < len(body) && body[end] != ',' && body[end] != '}' {
+		end++
+	}
+	return end, end > pos
+}
+
+// unquoteJS returns the contents of a single- or double-quoted JS string.
+// The keys and hashes it is used for contain no escapes worth decoding.
+func unquoteJS(value []byte) string {
+	value = bytes.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return string(value[1 : len(value)-1])
+	}
+	return string(value)
+}
+
+// parseDataBlocks decodes every AF_initDataCallback block in a page, keyed by
+// its ds key. Blocks whose data isn't valid JSON are skipped.
+func parseDataBlocks(body []byte) map[string]interface{} {
+	dataBlocks := make(map[string]interface{})
+	for _, block := range extractInitData(body) {
+		var data interface{}
+		if err := json.Unmarshal(block.Data, &data); err != nil {
+			continue
+		}
+		dataBlocks[block.Key] = data
+	}
+	return dataBlocks
+}
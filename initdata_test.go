@@ -0,0 +1,147 @@
+package googleplayscraper
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestExtractInitData(t *testing.T) {
+	page := []byte(`<script>function AF_initDataCallback(a){}</script>
+<script nonce="x">AF_initDataCallback({key: 'ds:1', hash: '7', data:[1,"a, sideChannel: b"], sideChannel: {}});</script>
+<script>AF_initDataCallback({hash: '9', isError: false, data:[{"k":"it's ]}"}],
+  key: 'ds:2', sideChannel: {"x": [1]}});</script>
+<script>AF_initDataCallback({key: "ds:3", data:
+[null]
+});</script>
+<script>AF_initDataCallback({key: 'ds:4', data:[1,2</script>`)
+
+	blocks := extractInitData(page)
+	if len(blocks) != 3 {
+		t.Fatalf("got %d blocks, want 3: %+v", len(blocks), blocks)
+	}
+
+	want := []struct{ key, hash, data string }{
+		{"ds:1", "7", `[1,"a, sideChannel: b"]`},
+		{"ds:2", "9", `[{"k":"it's ]}"}]`},
+		{"ds:3", "", `[null]`},
+	}
+	for i, w := range want {
+		b := blocks[i]
+		if b.Key != w.key || b.Hash != w.hash || string(b.Data) != w.data {
+			t.Errorf("block %d = {%s %s %s}, want {%s %s %s}", i, b.Key, b.Hash, b.Data, w.key, w.hash, w.data)
+		}
+	}
+
+	data := parseDataBlocks(page)
+	if s := getPath(data["ds:2"], 0); s == nil {
+		t.Errorf("ds:2 not decoded: %v", data)
+	}
+	if _, ok := data["ds:4"]; ok {
+		t.Error("truncated block should be skipped")
+	}
+}
+
+func TestExtractInitDataFixtures(t *testing.T) {
+	for _, name := range []string{"testdata/details_full.html", "testdata/preregister.html"} {
+		page, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+		data := parseDataBlocks(page)
+		if _, ok := data["ds:5"]; !ok {
+			t.Errorf("%s: ds:5 missing, got %d blocks", name, len(data))
+		}
+		for _, b := range extractInitData(page) {
+			if !json.Valid(b.Data) {
+				t.Errorf("%s: %s data is not valid JSON", name, b.Key)
+			}
+		}
+	}
+}
+
+// legacyDataRegex is the pattern the extractor replaced, kept as a benchmark baseline
+var legacyDataRegex = regexp.MustCompile(`AF_initDataCallback\(\{key:\s*'(ds:\d+)'.*?data:(.*?), sideChannel:`)
+
+// benchmarkPages are the pages the extractor benchmarks run on: every
+// captured page under testdata/pages (see TestCapturePages) plus a synthetic
+// page padded to the size of a real one
+func benchmarkPages(b *testing.B) map[string][]byte {
+	pages := make(map[string][]byte)
+	paths, _ := filepath.Glob("testdata/pages/*.html")
+	for _, path := range paths {
+		page, err := os.ReadFile(path)
+		if err != nil {
+			b.Fatalf("read fixture: %v", err)
+		}
+		pages[strings.TrimSuffix(filepath.Base(path), ".html")] = page
+	}
+	if len(paths) == 0 {
+		b.Log("no captured pages in testdata/pages; run TestCapturePages with GPLAY_CAPTURE_PAGES=1")
+	}
+
+	// Real pages carry a few hundred KB of markup, styles and scripts around
+	// the data blocks, which is what the regex has to scan past
+	small, err := os.ReadFile("testdata/details_full.html")
+	if err != nil {
+		b.Fatalf("read fixture: %v", err)
+	}
+	filler := strings.Repeat(`<div class="VfPpkd-Bz112c"><span jsname="x">'data:[' + "]"</span></div><script>var a={key:1};</script>`+"\n", 3000)
+	pages["synthetic_details"] = []byte(filler + string(small) + filler)
+	return pages
+}
+
+func benchmarkEachPage(b *testing.B, fn func([]byte)) {
+	for name, page := range benchmarkPages(b) {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(page)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				fn(page)
+			}
+		})
+	}
+}
+
+func BenchmarkExtractInitData(b *testing.B) {
+	benchmarkEachPage(b, func(page []byte) { extractInitData(page) })
+}
+
+func BenchmarkExtractInitDataRegex(b *testing.B) {
+	benchmarkEachPage(b, func(page []byte) { legacyDataRegex.FindAllStringSubmatch(string(page), -1) })
+}
+
+func BenchmarkParseDataBlocks(b *testing.B) {
+	benchmarkEachPage(b, func(page []byte) { parseDataBlocks(page) })
+}
+
+// TestCapturePages downloads a details page and a search page from Google
+// Play into testdata/pages for the benchmarks. It only runs when
+// GPLAY_CAPTURE_PAGES is set.
+func TestCapturePages(t *testing.T) {
+	if os.Getenv("GPLAY_CAPTURE_PAGES") == "" {
+		t.Skip("set GPLAY_CAPTURE_PAGES=1 to capture benchmark pages")
+	}
+	c := NewClient()
+	pages := map[string]string{
+		"details": BaseURL + "/store/apps/details?id=com.spotify.music&hl=en&gl=us",
+		"search":  BaseURL + "/store/search?q=music&c=apps&hl=en&gl=us",
+	}
+	if err := os.MkdirAll("testdata/pages", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, url := range pages {
+		body, err := c.get(context.Background(), url)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join("testdata/pages", name+".html"), body, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Logf("%s: %d bytes", name, len(body))
+	}
+}
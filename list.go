@@ -2,9 +2,7 @@ package googleplayscraper
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"strings"
 )
 
 // Age represents age rating filter for app lists
@@ -68,53 +66,36 @@ func (c *Client) List(ctx context.Context, opts ListOptions) ([]SearchResult, er
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	results, err := parseListPage(body, opts)
+	rep := c.newReport(EndpointList, reqURL)
+	results, err := parseListPage(body, opts, rep)
 	if err != nil {
 		return nil, err
 	}
+	if err := c.sendReport(rep, len(results)); err != nil {
+		return nil, err
+	}
 
 	// Fetch full details if requested
 	if opts.FullDetail {
 		return c.enrichSearchResults(ctx, results, opts.Lang, opts.Country)
 	}
 
-	return results, nil
+	return c.rawResults(results), nil
 }
 
-func parseListPage(body []byte, opts ListOptions) ([]SearchResult, error) {
-	html := string(body)
-
-	// Find data blocks
-	dataBlocks := make(map[string]interface{})
-	matches := scriptDataRegex.FindAllStringSubmatch(html, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 {
-			continue
-		}
-		key := match[1]
-		dataStr := strings.TrimSpace(match[2])
-
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			continue
-		}
-		dataBlocks[key] = data
-	}
+func parseListPage(body []byte, opts ListOptions, rep *ParseReport) ([]SearchResult, error) {
+	dataBlocks := parseDataBlocks(body)
 
 	// Apps are in ds:4[0][1][x][21][0]
 	ds4, ok := dataBlocks["ds:4"]
 	if !ok {
+		rep.mismatch("ds:4", "data block not found")
 		return nil, nil
 	}
 
-	sections := getPath(ds4, 0, 1)
-	if sections == nil {
-		return nil, nil
-	}
-
-	sectionsArr, ok := sections.([]interface{})
+	sectionsArr, ok := getPath(ds4, 0, 1).([]interface{})
 	if !ok {
+		rep.mismatch("ds:4[0][1]", "sections not found")
 		return nil, nil
 	}
 
@@ -138,9 +119,11 @@ func parseListPage(body []byte, opts ListOptions) ([]SearchResult, error) {
 		if apps != nil {
 			appsArr, ok := apps.([]interface{})
 			if ok {
-				for _, app := range appsArr {
-					result := parseListApp(app)
+				for i, app := range appsArr {
+					rep.at("ds:4[0][1][%d][21][0][%d]", sectionIndex, i)
+					result := parseListApp(app, rep)
 					if result.AppID != "" {
+						result.Raw.Blocks = dataBlocks
 						results = append(results, result)
 					}
 					if len(results) >= opts.Num {
@@ -153,7 +136,8 @@ func parseListPage(body []byte, opts ListOptions) ([]SearchResult, error) {
 
 	// If no results from target section, try all sections
 	if len(results) == 0 {
-		for _, section := range sectionsArr {
+		rep.mismatch(fmt.Sprintf("ds:4[0][1][%d][21][0]", sectionIndex), "no apps in the %s section, trying the others", opts.Collection)
+		for s, section := range sectionsArr {
 			apps := getPath(section, 21, 0)
 			if apps == nil {
 				continue
@@ -162,9 +146,11 @@ func parseListPage(body []byte, opts ListOptions) ([]SearchResult, error) {
 			if !ok {
 				continue
 			}
-			for _, app := range appsArr {
-				result := parseListApp(app)
+			for i, app := range appsArr {
+				rep.at("ds:4[0][1][%d][21][0][%d]", s, i)
+				result := parseListApp(app, rep)
 				if result.AppID != "" {
+					result.Raw.Blocks = dataBlocks
 					results = append(results, result)
 				}
 				if len(results) >= opts.Num {
@@ -180,69 +166,9 @@ func parseListPage(body []byte, opts ListOptions) ([]SearchResult, error) {
 	return results, nil
 }
 
-func parseListApp(item interface{}) SearchResult {
-	arr, ok := item.([]interface{})
-	if !ok {
+func parseListApp(item interface{}, rep *ParseReport) SearchResult {
+	if _, ok := item.([]interface{}); !ok {
 		return SearchResult{}
 	}
-
-	result := SearchResult{}
-
-	// AppID at [0][0]
-	if v := getPath(arr, 0, 0); v != nil {
-		result.AppID = toString(v)
-	}
-
-	// Title at [3]
-	if v := getPath(arr, 3); v != nil {
-		result.Title = toString(v)
-	}
-
-	// Icon at [1][3][2]
-	if v := getPath(arr, 1, 3, 2); v != nil {
-		result.Icon = toString(v)
-	}
-
-	// Developer at [14]
-	if v := getPath(arr, 14); v != nil {
-		result.Developer = toString(v)
-	}
-
-	// Score at [4][1]
-	if v := getPath(arr, 4, 1); v != nil {
-		result.Score = toFloat64(v)
-	}
-
-	// ScoreText at [4][0]
-	if v := getPath(arr, 4, 0); v != nil {
-		result.ScoreText = toString(v)
-	}
-
-	// Price info at [8]
-	if priceInfo := getPath(arr, 8); priceInfo != nil {
-		if priceArr, ok := priceInfo.([]interface{}); ok && len(priceArr) > 1 {
-			// Check if free
-			if v := getPath(priceArr, 1, 0, 0); v != nil {
-				price := toFloat64(v)
-				result.Price = price / 1000000
-				result.Free = price == 0
-			} else {
-				result.Free = true
-			}
-			// Currency
-			if v := getPath(priceArr, 1, 0, 1); v != nil {
-				result.Currency = toString(v)
-			}
-		} else {
-			result.Free = true
-		}
-	} else {
-		result.Free = true
-	}
-
-	if result.AppID != "" {
-		result.URL = fmt.Sprintf("%s/store/apps/details?id=%s", BaseURL, result.AppID)
-	}
-
-	return result
+	return parseCard(item, cardFields, rep)
 }
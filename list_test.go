@@ -178,7 +178,7 @@ func TestListOptionsWithFullDetail(t *testing.T) {
 
 func TestParseListPage(t *testing.T) {
 	// Case 1: Empty body
-	res, err := parseListPage([]byte{}, ListOptions{Num: 10})
+	res, err := parseListPage([]byte{}, ListOptions{Num: 10}, nil)
 	// Expect nil, nil because regex won't match keys, loops finish, returns empty slice, no error
 	if err != nil {
 		t.Errorf("unexpected error for empty body: %v", err)
@@ -190,7 +190,7 @@ func TestParseListPage(t *testing.T) {
 	// Case 2: Invalid JSON in data blocks
 	// Should be ignored
 	body := `<script>AF_initDataCallback({key: 'ds:3', isError: false , hash: '1', data: {invalid}});</script>`
-	res, err = parseListPage([]byte(body), ListOptions{Num: 10})
+	res, err = parseListPage([]byte(body), ListOptions{Num: 10}, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -214,7 +214,7 @@ func TestParseListApp(t *testing.T) {
 	item[14] = "Dev Name"
 	item[0] = []interface{}{"com.test.app"}
 
-	res := parseListApp(item)
+	res := parseListApp(item, nil)
 	if res.AppID != "com.test.app" {
 		t.Errorf("expected com.test.app, got %q", res.AppID)
 	}
@@ -223,7 +223,7 @@ func TestParseListApp(t *testing.T) {
 	}
 
 	// Case 2: Malformed input
-	res2 := parseListApp("not-array")
+	res2 := parseListApp("not-array", nil)
 	if res2.AppID != "" {
 		t.Error("expected empty result for malformed input")
 	}
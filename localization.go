@@ -0,0 +1,181 @@
+package googleplayscraper
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// LocalizationOptions configures a multi-language listing comparison
+type LocalizationOptions struct {
+	Country     string
+	PrimaryLang string // Reference listing other languages are compared to (default "en")
+}
+
+// LocaleListing summarises the store listing in one language
+type LocaleListing struct {
+	Lang              string   `json:"lang"`
+	Title             string   `json:"title"`
+	TitleLength       int      `json:"titleLength"` // Lengths are in characters, not bytes
+	SummaryLength     int      `json:"summaryLength"`
+	DescriptionLength int      `json:"descriptionLength"`
+	Fallback          bool     `json:"fallback"` // Google Play served the primary listing instead of a translation
+	Stale             bool     `json:"stale"`
+	StaleReasons      []string `json:"staleReasons,omitempty"`
+	Error             string   `json:"error,omitempty"`
+}
+
+// LocalizationReport compares translated listings against the primary one
+type LocalizationReport struct {
+	AppID       string          `json:"appId"`
+	PrimaryLang string          `json:"primaryLang"`
+	Primary     LocaleListing   `json:"primary"`
+	Listings    []LocaleListing `json:"listings"`
+}
+
+// Fallbacks returns languages that have no translated listing
+func (r *LocalizationReport) Fallbacks() []string {
+	var langs []string
+	for _, l := range r.Listings {
+		if l.Fallback {
+			langs = append(langs, l.Lang)
+		}
+	}
+	return langs
+}
+
+// Stale returns languages whose description appears out of date
+func (r *LocalizationReport) Stale() []string {
+	var langs []string
+	for _, l := range r.Listings {
+		if l.Stale {
+			langs = append(langs, l.Lang)
+		}
+	}
+	return langs
+}
+
+// Localizations fetches the listing in each language and compares it with the primary one.
+// A listing identical to the primary is reported as a fallback. A translated description is
+// reported as stale when its paragraph count differs from the primary or it lacks numbers
+// and links the primary mentions, which usually means the primary was updated since.
+func (c *Client) Localizations(ctx context.Context, appID string, langs []string, opts LocalizationOptions) (*LocalizationReport, error) {
+	if appID == "" {
+		return nil, fmt.Errorf("appID is required")
+	}
+	if len(langs) == 0 {
+		return nil, fmt.Errorf("at least one language is required")
+	}
+	if opts.PrimaryLang == "" {
+		opts.PrimaryLang = "en"
+	}
+
+	primary, err := c.App(ctx, appID, AppOptions{Lang: opts.PrimaryLang, Country: opts.Country})
+	if err != nil {
+		return nil, fmt.Errorf("primary listing: %w", err)
+	}
+
+	report := &LocalizationReport{
+		AppID:       appID,
+		PrimaryLang: opts.PrimaryLang,
+		Primary:     localeListing(opts.PrimaryLang, primary, nil),
+	}
+
+	for _, lang := range langs {
+		if lang == opts.PrimaryLang {
+			continue
+		}
+		app, err := c.App(ctx, appID, AppOptions{Lang: lang, Country: opts.Country})
+		if err != nil {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			report.Listings = append(report.Listings, LocaleListing{Lang: lang, Error: err.Error()})
+			continue
+		}
+		report.Listings = append(report.Listings, localeListing(lang, app, primary))
+	}
+
+	return report, nil
+}
+
+// localeListing describes app in lang; primary is nil for the primary listing itself
+func localeListing(lang string, app, primary *App) LocaleListing {
+	listing := LocaleListing{
+		Lang:              lang,
+		Title:             app.Title,
+		TitleLength:       utf8.RuneCountInString(app.Title),
+		SummaryLength:     utf8.RuneCountInString(app.Summary),
+		DescriptionLength: utf8.RuneCountInString(app.Description),
+	}
+
+	if primary == nil {
+		return listing
+	}
+
+	if app.Description == primary.Description && app.Summary == primary.Summary {
+		listing.Fallback = true
+		return listing
+	}
+
+	if got, want := countParagraphs(app.Description), countParagraphs(primary.Description); got != want {
+		listing.StaleReasons = append(listing.StaleReasons,
+			fmt.Sprintf("has %d paragraphs, primary has %d", got, want))
+	}
+
+	if missing := missingTokens(primary.Description, app.Description); len(missing) > 0 {
+		listing.StaleReasons = append(listing.StaleReasons,
+			fmt.Sprintf("missing %s from primary", strings.Join(missing, ", ")))
+	}
+
+	listing.Stale = len(listing.StaleReasons) > 0
+	return listing
+}
+
+func countParagraphs(s string) int {
+	n := 0
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// tokenRegex matches language-neutral content: links and numbers
+var tokenRegex = regexp.MustCompile(`https?://[^\s<>"]+|\d+(?:[.,\s]\d{3})*(?:[.,]\d+)?`)
+
+// missingTokens returns links and numbers in primary that translated lacks.
+// Digit grouping is ignored so "1,000" matches "1.000" and "1 000".
+func missingTokens(primary, translated string) []string {
+	have := make(map[string]bool)
+	for _, tok := range tokenRegex.FindAllString(translated, -1) {
+		have[normalizeToken(tok)] = true
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, tok := range tokenRegex.FindAllString(primary, -1) {
+		norm := normalizeToken(tok)
+		if have[norm] || seen[norm] {
+			continue
+		}
+		seen[norm] = true
+		missing = append(missing, tok)
+	}
+	return missing
+}
+
+func normalizeToken(tok string) string {
+	if strings.HasPrefix(tok, "http") {
+		return strings.TrimRight(tok, ".,;:!?)")
+	}
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, tok)
+}
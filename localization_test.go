@@ -0,0 +1,92 @@
+package googleplayscraper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestLocalizationsValidation(t *testing.T) {
+	c := NewClient()
+
+	if _, err := c.Localizations(context.Background(), "", []string{"de"}, LocalizationOptions{}); err == nil {
+		t.Error("expected error for empty appID")
+	}
+	if _, err := c.Localizations(context.Background(), "com.example", nil, LocalizationOptions{}); err == nil {
+		t.Error("expected error for empty language list")
+	}
+}
+
+func TestLocaleListing(t *testing.T) {
+	primary := &App{
+		Title:       "Weather",
+		Summary:     "Forecasts for 10,000 cities",
+		Description: "Forecasts for 10,000 cities.\nNew in 2026: radar maps.\nSupport: https://example.com/help",
+	}
+
+	t.Run("primary", func(t *testing.T) {
+		l := localeListing("en", primary, nil)
+		if l.TitleLength != 7 || l.Fallback || l.Stale {
+			t.Errorf("unexpected primary listing: %+v", l)
+		}
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		l := localeListing("pt", primary, primary)
+		if !l.Fallback {
+			t.Error("identical listing should be a fallback")
+		}
+		if l.Stale {
+			t.Error("fallback should not be reported as stale")
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		de := &App{
+			Title:       "Wetter",
+			Summary:     "Vorhersagen für 10.000 Städte",
+			Description: "Vorhersagen für 10.000 Städte.\nNeu in 2026: Radarkarten.\nHilfe: https://example.com/help",
+		}
+		l := localeListing("de", de, primary)
+		if l.Fallback || l.Stale {
+			t.Errorf("unexpected listing: %+v", l)
+		}
+		if l.SummaryLength != 29 {
+			t.Errorf("SummaryLength counts characters: got %d, want 29", l.SummaryLength)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		fr := &App{
+			Title:       "Météo",
+			Summary:     "Prévisions pour 10 000 villes",
+			Description: "Prévisions pour 10 000 villes.",
+		}
+		l := localeListing("fr", fr, primary)
+		if !l.Stale {
+			t.Fatal("expected stale listing")
+		}
+		want := []string{
+			"has 1 paragraphs, primary has 3",
+			"missing 2026, https://example.com/help from primary",
+		}
+		if !reflect.DeepEqual(l.StaleReasons, want) {
+			t.Errorf("StaleReasons: got %q, want %q", l.StaleReasons, want)
+		}
+	})
+}
+
+func TestLocalizationReportFilters(t *testing.T) {
+	r := &LocalizationReport{Listings: []LocaleListing{
+		{Lang: "de"},
+		{Lang: "pt", Fallback: true},
+		{Lang: "fr", Stale: true},
+	}}
+
+	if got := r.Fallbacks(); !reflect.DeepEqual(got, []string{"pt"}) {
+		t.Errorf("Fallbacks: got %v", got)
+	}
+	if got := r.Stale(); !reflect.DeepEqual(got, []string{"fr"}) {
+		t.Errorf("Stale: got %v", got)
+	}
+}
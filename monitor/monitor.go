@@ -0,0 +1,376 @@
+// Package monitor polls a watchlist of apps and reports changes as events.
+//
+// State is kept in a snapshot.Store, so a monitor restarted with the same
+// store continues where it left off instead of re-reporting old changes.
+// The first poll of an app only records a baseline and emits no events.
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+	"github.com/kryuchenko/google-play-scraper/snapshot"
+)
+
+// Source is the part of *googleplayscraper.Client the monitor uses
+type Source interface {
+	App(ctx context.Context, appID string, opts gplay.AppOptions) (*gplay.App, error)
+	Reviews(ctx context.Context, appID string, opts gplay.ReviewOptions) (*gplay.ReviewsResult, error)
+	Permissions(ctx context.Context, opts gplay.PermissionsOptions) ([]gplay.Permission, error)
+	DataSafety(ctx context.Context, opts gplay.DataSafetyOptions) (*gplay.DataSafety, error)
+}
+
+// EventType identifies the kind of change detected
+type EventType string
+
+const (
+	EventNewVersion        EventType = "new_version"
+	EventLowScoreReview    EventType = "low_score_review"
+	EventNewPermission     EventType = "new_permission"
+	EventDataSafetyChanged EventType = "data_safety_changed"
+)
+
+// Event is a change delivered to webhooks and the handler
+type Event struct {
+	Type               EventType          `json:"type"`
+	AppID              string             `json:"appId"`
+	Time               time.Time          `json:"time"`
+	Diff               *gplay.AppDiff     `json:"diff,omitempty"`
+	Review             *gplay.Review      `json:"review,omitempty"`
+	Permissions        []gplay.Permission `json:"permissions,omitempty"` // Newly added permissions
+	DataSafety         *gplay.DataSafety  `json:"dataSafety,omitempty"`
+	PreviousDataSafety *gplay.DataSafety  `json:"previousDataSafety,omitempty"`
+}
+
+// kindReviewCursor stores the date of the newest review already seen
+const kindReviewCursor snapshot.Kind = "review_cursor"
+
+// Config configures a Monitor
+type Config struct {
+	Apps    []string // App IDs to watch
+	Lang    string
+	Country string
+
+	// Poll intervals per check; zero disables that check
+	AppInterval         time.Duration
+	ReviewsInterval     time.Duration
+	PermissionsInterval time.Duration
+	DataSafetyInterval  time.Duration
+
+	LowScore int // Reviews with this score or lower trigger events (default 2)
+
+	Store    snapshot.Store // Persists state between restarts (required)
+	Webhooks []string       // URLs that receive each event as a JSON POST; failed posts are retried on the next poll
+	Handler  func(Event)    // Called for each event, concurrently from the goroutines of the enabled checks
+	OnError  func(error)    // Called for failed checks and deliveries
+
+	HTTPClient *http.Client // Used for webhooks (default: 10s timeout)
+}
+
+// Monitor polls apps and delivers change events
+type Monitor struct {
+	src Source
+	cfg Config
+	now func() time.Time
+
+	pendingMu sync.Mutex // Serializes updates of the pending event queues
+}
+
+// New creates a monitor for the given source and configuration
+func New(src Source, cfg Config) (*Monitor, error) {
+	if src == nil {
+		return nil, fmt.Errorf("source is required")
+	}
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	if cfg.Lang == "" {
+		cfg.Lang = "en"
+	}
+	if cfg.Country == "" {
+		cfg.Country = "us"
+	}
+	if cfg.LowScore == 0 {
+		cfg.LowScore = 2
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Monitor{src: src, cfg: cfg, now: time.Now}, nil
+}
+
+// Run polls every enabled check on its interval until ctx is cancelled.
+// Each check runs once immediately on start.
+func (m *Monitor) Run(ctx context.Context) error {
+	checks := []struct {
+		interval time.Duration
+		check    func(context.Context, string) ([]Event, error)
+	}{
+		{m.cfg.AppInterval, m.CheckApp},
+		{m.cfg.ReviewsInterval, m.CheckReviews},
+		{m.cfg.PermissionsInterval, m.CheckPermissions},
+		{m.cfg.DataSafetyInterval, m.CheckDataSafety},
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		if c.interval <= 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(interval time.Duration, check func(context.Context, string) ([]Event, error)) {
+			defer wg.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				m.poll(ctx, check)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(c.interval, c.check)
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// poll retries each watched app's undelivered events, then runs check for it
+// and delivers the resulting events. Events a webhook failed to receive are
+// queued in the store, since the check has already saved the state they
+// report.
+func (m *Monitor) poll(ctx context.Context, check func(context.Context, string) ([]Event, error)) {
+	for _, appID := range m.cfg.Apps {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := m.retryPending(ctx, appID); err != nil {
+			m.reportError(fmt.Errorf("retry %s: %w", appID, err))
+		}
+		events, err := check(ctx, appID)
+		if err != nil {
+			m.reportError(fmt.Errorf("check %s: %w", appID, err))
+		}
+		var failed []pendingEvent
+		for _, e := range events {
+			if urls := m.deliver(ctx, e); len(urls) > 0 {
+				failed = append(failed, pendingEvent{Event: e, Webhooks: urls})
+			}
+		}
+		if err := m.addPending(ctx, appID, failed); err != nil {
+			m.reportError(fmt.Errorf("queue events for %s: %w", appID, err))
+		}
+	}
+}
+
+// CheckApp fetches app details and reports a new version
+func (m *Monitor) CheckApp(ctx context.Context, appID string) ([]Event, error) {
+	app, err := m.src.App(ctx, appID, gplay.AppOptions{Lang: m.cfg.Lang, Country: m.cfg.Country})
+	if app == nil {
+		return nil, err // Unavailable listings still return an app and are recorded
+	}
+
+	prev, err := snapshot.Latest(ctx, m.cfg.Store, appID, snapshot.KindApp)
+	if err != nil {
+		return nil, err
+	}
+	now := m.now()
+	stored := *app
+	stored.Raw = nil // Page data set by WithRawData isn't state
+	rec, err := snapshot.NewRecord(appID, snapshot.KindApp, now, &stored)
+	if err != nil {
+		return nil, err
+	}
+
+	var prevApp *gplay.App
+	if prev != nil {
+		if prevApp, err = prev.App(); err != nil {
+			return nil, err
+		}
+		if unchanged(prevApp, rec) {
+			return nil, nil
+		}
+	}
+	if err := m.cfg.Store.Append(ctx, rec); err != nil {
+		return nil, err
+	}
+	if prev == nil {
+		return nil, nil
+	}
+
+	diff := gplay.DiffApps(prevApp, app)
+	if !diff.VersionChanged {
+		return nil, nil
+	}
+	return []Event{{Type: EventNewVersion, AppID: appID, Time: now, Diff: &diff}}, nil
+}
+
+// CheckReviews reports new reviews at or below the low-score threshold
+func (m *Monitor) CheckReviews(ctx context.Context, appID string) ([]Event, error) {
+	result, err := m.src.Reviews(ctx, appID, gplay.ReviewOptions{
+		Lang:    m.cfg.Lang,
+		Country: m.cfg.Country,
+		Sort:    gplay.SortNewest,
+		Count:   100,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prev, err := snapshot.Latest(ctx, m.cfg.Store, appID, kindReviewCursor)
+	if err != nil {
+		return nil, err
+	}
+	var cursor time.Time
+	if prev != nil {
+		if err := json.Unmarshal(prev.Data, &cursor); err != nil {
+			return nil, fmt.Errorf("decode review cursor: %w", err)
+		}
+	}
+
+	now := m.now()
+	newest := cursor
+	var events []Event
+	for i := range result.Reviews {
+		r := result.Reviews[i]
+		if !r.Date.After(cursor) {
+			continue
+		}
+		if r.Date.After(newest) {
+			newest = r.Date
+		}
+		if prev != nil && r.Score > 0 && r.Score <= m.cfg.LowScore {
+			events = append(events, Event{Type: EventLowScoreReview, AppID: appID, Time: now, Review: &r})
+		}
+	}
+
+	if newest.After(cursor) || prev == nil {
+		rec, err := snapshot.NewRecord(appID, kindReviewCursor, now, newest)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.cfg.Store.Append(ctx, rec); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// CheckPermissions reports permissions that were not present in the previous snapshot
+func (m *Monitor) CheckPermissions(ctx context.Context, appID string) ([]Event, error) {
+	perms, err := m.src.Permissions(ctx, gplay.PermissionsOptions{AppID: appID, Lang: m.cfg.Lang, Country: m.cfg.Country})
+	if err != nil {
+		return nil, err
+	}
+
+	prev, err := snapshot.Latest(ctx, m.cfg.Store, appID, snapshot.KindPermissions)
+	if err != nil {
+		return nil, err
+	}
+	// Compare and store the modeled fields only, as for data safety
+	current := make([]gplay.Permission, len(perms))
+	for i, p := range perms {
+		p.Raw = nil
+		current[i] = p
+	}
+	now := m.now()
+	rec, err := snapshot.NewRecord(appID, snapshot.KindPermissions, now, current)
+	if err != nil {
+		return nil, err
+	}
+
+	var prevPerms []gplay.Permission
+	if prev != nil {
+		if prevPerms, err = prev.Permissions(); err != nil {
+			return nil, err
+		}
+		if unchanged(prevPerms, rec) {
+			return nil, nil
+		}
+	}
+	if err := m.cfg.Store.Append(ctx, rec); err != nil {
+		return nil, err
+	}
+	if prev == nil {
+		return nil, nil
+	}
+
+	known := make(map[gplay.Permission]bool, len(prevPerms))
+	for _, p := range prevPerms {
+		known[p] = true
+	}
+	var added []gplay.Permission
+	for _, p := range current {
+		if !known[p] {
+			added = append(added, p)
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	return []Event{{Type: EventNewPermission, AppID: appID, Time: now, Permissions: added}}, nil
+}
+
+// CheckDataSafety reports any change in the data safety section
+func (m *Monitor) CheckDataSafety(ctx context.Context, appID string) ([]Event, error) {
+	ds, err := m.src.DataSafety(ctx, gplay.DataSafetyOptions{AppID: appID, Lang: m.cfg.Lang, Country: m.cfg.Country})
+	if err != nil {
+		return nil, err
+	}
+	if ds == nil {
+		return nil, errors.New("data safety section not found")
+	}
+
+	prev, err := snapshot.Latest(ctx, m.cfg.Store, appID, snapshot.KindDataSafety)
+	if err != nil {
+		return nil, err
+	}
+	// Compare and store the modeled fields only; the page data set by
+	// WithRawData changes with every response
+	current := *ds
+	current.Raw = nil
+	now := m.now()
+	rec, err := snapshot.NewRecord(appID, snapshot.KindDataSafety, now, &current)
+	if err != nil {
+		return nil, err
+	}
+
+	var prevDS *gplay.DataSafety
+	if prev != nil {
+		if prevDS, err = prev.DataSafety(); err != nil {
+			return nil, err
+		}
+		prevDS.Raw = nil
+		if unchanged(prevDS, rec) {
+			return nil, nil
+		}
+	}
+	if err := m.cfg.Store.Append(ctx, rec); err != nil {
+		return nil, err
+	}
+	if prev == nil {
+		return nil, nil
+	}
+	return []Event{{Type: EventDataSafetyChanged, AppID: appID, Time: now, DataSafety: &current, PreviousDataSafety: prevDS}}, nil
+}
+
+// unchanged reports whether rec holds the same data as the previous
+// snapshot prev. prev is re-encoded so both sides use the current fields.
+func unchanged(prev interface{}, rec snapshot.Record) bool {
+	before, err := json.Marshal(prev)
+	return err == nil && string(before) == string(rec.Data)
+}
+
+func (m *Monitor) reportError(err error) {
+	if m.cfg.OnError != nil {
+		m.cfg.OnError(err)
+	}
+}
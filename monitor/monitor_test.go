@@ -0,0 +1,376 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+	"github.com/kryuchenko/google-play-scraper/playtest"
+	"github.com/kryuchenko/google-play-scraper/snapshot"
+)
+
+// fakeSource serves whatever the test puts in it
+type fakeSource struct {
+	mu          sync.Mutex
+	app         *gplay.App
+	reviews     []gplay.Review
+	permissions []gplay.Permission
+	dataSafety  *gplay.DataSafety
+}
+
+func (f *fakeSource) App(ctx context.Context, appID string, opts gplay.AppOptions) (*gplay.App, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	app := *f.app
+	return &app, nil
+}
+
+func (f *fakeSource) Reviews(ctx context.Context, appID string, opts gplay.ReviewOptions) (*gplay.ReviewsResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &gplay.ReviewsResult{Reviews: append([]gplay.Review(nil), f.reviews...)}, nil
+}
+
+func (f *fakeSource) Permissions(ctx context.Context, opts gplay.PermissionsOptions) ([]gplay.Permission, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]gplay.Permission(nil), f.permissions...), nil
+}
+
+func (f *fakeSource) DataSafety(ctx context.Context, opts gplay.DataSafetyOptions) (*gplay.DataSafety, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ds := *f.dataSafety
+	return &ds, nil
+}
+
+func newTestMonitor(t *testing.T, src Source, store snapshot.Store) *Monitor {
+	t.Helper()
+	m, err := New(src, Config{Apps: []string{"com.example"}, Store: store})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	m.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+	return m
+}
+
+func TestNewValidation(t *testing.T) {
+	if _, err := New(nil, Config{Store: snapshot.NewMemoryStore()}); err == nil {
+		t.Error("expected error for nil source")
+	}
+	if _, err := New(&fakeSource{}, Config{}); err == nil {
+		t.Error("expected error for nil store")
+	}
+}
+
+func TestCheckApp(t *testing.T) {
+	ctx := context.Background()
+	srv := playtest.NewServer()
+	defer srv.Close()
+	app := gplay.App{AppID: "com.example", Title: "Example", Version: "1.0", Score: 4.0}
+	srv.AddApp(app)
+	m := newTestMonitor(t, srv.Client(), snapshot.NewMemoryStore())
+
+	if events, err := m.CheckApp(ctx, "com.example"); err != nil || len(events) != 0 {
+		t.Fatalf("baseline: got %v, %v", events, err)
+	}
+
+	app.Score = 4.2
+	srv.AddApp(app)
+	if events, err := m.CheckApp(ctx, "com.example"); err != nil || len(events) != 0 {
+		t.Fatalf("score change alone should not emit: got %v, %v", events, err)
+	}
+
+	app.Version = "1.1"
+	srv.AddApp(app)
+	events, err := m.CheckApp(ctx, "com.example")
+	if err != nil {
+		t.Fatalf("CheckApp: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventNewVersion {
+		t.Fatalf("expected new version event, got %+v", events)
+	}
+	if events[0].Diff == nil || !events[0].Diff.VersionChanged {
+		t.Errorf("event should carry the diff: %+v", events[0].Diff)
+	}
+}
+
+func TestCheckReviews(t *testing.T) {
+	ctx := context.Background()
+	day := func(n int) time.Time { return time.Date(2026, 2, n, 0, 0, 0, 0, time.UTC) }
+	src := &fakeSource{reviews: []gplay.Review{
+		{ID: "old-bad", Score: 1, Date: day(1)},
+		{ID: "old-good", Score: 5, Date: day(2)},
+	}}
+	m := newTestMonitor(t, src, snapshot.NewMemoryStore())
+
+	if events, err := m.CheckReviews(ctx, "com.example"); err != nil || len(events) != 0 {
+		t.Fatalf("baseline should not report existing reviews: got %v, %v", events, err)
+	}
+
+	src.reviews = append([]gplay.Review{
+		{ID: "new-bad", Score: 2, Date: day(4)},
+		{ID: "new-ok", Score: 3, Date: day(3)},
+	}, src.reviews...)
+
+	events, err := m.CheckReviews(ctx, "com.example")
+	if err != nil {
+		t.Fatalf("CheckReviews: %v", err)
+	}
+	if len(events) != 1 || events[0].Review.ID != "new-bad" {
+		t.Fatalf("expected one low-score event for new-bad, got %+v", events)
+	}
+
+	if events, _ := m.CheckReviews(ctx, "com.example"); len(events) != 0 {
+		t.Errorf("reviews should be reported once, got %+v", events)
+	}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	ctx := context.Background()
+	src := &fakeSource{permissions: []gplay.Permission{{Type: "Camera", Permission: "take pictures"}}}
+	m := newTestMonitor(t, src, snapshot.NewMemoryStore())
+
+	if events, err := m.CheckPermissions(ctx, "com.example"); err != nil || len(events) != 0 {
+		t.Fatalf("baseline: got %v, %v", events, err)
+	}
+
+	src.permissions = append(src.permissions, gplay.Permission{Type: "Location", Permission: "precise location"})
+	events, err := m.CheckPermissions(ctx, "com.example")
+	if err != nil {
+		t.Fatalf("CheckPermissions: %v", err)
+	}
+	if len(events) != 1 || len(events[0].Permissions) != 1 || events[0].Permissions[0].Type != "Location" {
+		t.Fatalf("expected new Location permission, got %+v", events)
+	}
+}
+
+func TestCheckDataSafety(t *testing.T) {
+	ctx := context.Background()
+	store := snapshot.NewMemoryStore()
+	src := &fakeSource{dataSafety: &gplay.DataSafety{PrivacyPolicyURL: "https://example.com/v1"}}
+	m := newTestMonitor(t, src, store)
+
+	for i := 0; i < 2; i++ {
+		if events, err := m.CheckDataSafety(ctx, "com.example"); err != nil || len(events) != 0 {
+			t.Fatalf("poll %d: got %v, %v", i, events, err)
+		}
+	}
+	if records, _ := store.List(ctx, "com.example", snapshot.Query{Kind: snapshot.KindDataSafety}); len(records) != 1 {
+		t.Errorf("unchanged data safety should be stored once, got %d records", len(records))
+	}
+
+	src.dataSafety = &gplay.DataSafety{PrivacyPolicyURL: "https://example.com/v2"}
+	events, err := m.CheckDataSafety(ctx, "com.example")
+	if err != nil {
+		t.Fatalf("CheckDataSafety: %v", err)
+	}
+	if len(events) != 1 || events[0].PreviousDataSafety.PrivacyPolicyURL != "https://example.com/v1" {
+		t.Fatalf("expected data safety change, got %+v", events)
+	}
+}
+
+func TestStatePersistsAcrossRestarts(t *testing.T) {
+	ctx := context.Background()
+	store, err := snapshot.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	src := &fakeSource{app: &gplay.App{AppID: "com.example", Version: "1.0"}}
+
+	first := newTestMonitor(t, src, store)
+	if _, err := first.CheckApp(ctx, "com.example"); err != nil {
+		t.Fatalf("CheckApp: %v", err)
+	}
+
+	src.app.Version = "2.0"
+	restarted := newTestMonitor(t, src, store)
+	events, err := restarted.CheckApp(ctx, "com.example")
+	if err != nil {
+		t.Fatalf("CheckApp: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != EventNewVersion {
+		t.Errorf("restarted monitor should detect the version change, got %+v", events)
+	}
+}
+
+func TestRunDeliversWebhooks(t *testing.T) {
+	received := make(chan Event, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type: got %q", ct)
+		}
+		var e Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+		}
+		received <- e
+	}))
+	defer server.Close()
+
+	store := snapshot.NewMemoryStore()
+	// Seed an older version so the first poll reports a change
+	if err := snapshot.SaveApp(context.Background(), store, &gplay.App{AppID: "com.example", Version: "1.0"}, time.Now()); err != nil {
+		t.Fatalf("SaveApp: %v", err)
+	}
+
+	var handled []Event
+	var mu sync.Mutex
+	src := &fakeSource{app: &gplay.App{AppID: "com.example", Version: "1.1"}}
+	m, err := New(src, Config{
+		Apps:        []string{"com.example"},
+		AppInterval: time.Hour,
+		Store:       store,
+		Webhooks:    []string{server.URL},
+		Handler: func(e Event) {
+			mu.Lock()
+			handled = append(handled, e)
+			mu.Unlock()
+		},
+		OnError: func(err error) { t.Errorf("unexpected error: %v", err) },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	select {
+	case e := <-received:
+		if e.Type != EventNewVersion || e.AppID != "com.example" {
+			t.Errorf("unexpected webhook event: %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not delivered")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run: got %v, want context.Canceled", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 1 {
+		t.Errorf("handler: got %d events, want 1", len(handled))
+	}
+}
+
+func TestUndeliveredEventsAreRetried(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	down := true
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var e Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("decode webhook body: %v", err)
+		}
+		received = append(received, e)
+	}))
+	defer server.Close()
+
+	store := snapshot.NewMemoryStore()
+	if err := snapshot.SaveApp(ctx, store, &gplay.App{AppID: "com.example", Version: "1.0"}, time.Now()); err != nil {
+		t.Fatalf("SaveApp: %v", err)
+	}
+	src := &fakeSource{app: &gplay.App{AppID: "com.example", Version: "1.1"}}
+	var errs int
+	m, err := New(src, Config{
+		Apps:     []string{"com.example"},
+		Store:    store,
+		Webhooks: []string{server.URL},
+		OnError:  func(error) { errs++ },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	m.poll(ctx, m.CheckApp)
+	if errs != 1 || len(received) != 0 {
+		t.Fatalf("failed delivery: %d errors, %d received", errs, len(received))
+	}
+
+	mu.Lock()
+	down = false
+	mu.Unlock()
+	m.poll(ctx, m.CheckApp) // Version unchanged since the last poll; only the retry delivers
+	m.poll(ctx, m.CheckApp)
+	if len(received) != 1 || received[0].Type != EventNewVersion {
+		t.Errorf("retried delivery: got %+v", received)
+	}
+}
+
+func TestDataSafetyIgnoresRawData(t *testing.T) {
+	ctx := context.Background()
+	store := snapshot.NewMemoryStore()
+	src := &fakeSource{dataSafety: &gplay.DataSafety{
+		PrivacyPolicyURL: "https://example.com/v1",
+		Raw:              &gplay.RawData{Node: []interface{}{"page", 1.0}},
+	}}
+	m := newTestMonitor(t, src, store)
+
+	if _, err := m.CheckDataSafety(ctx, "com.example"); err != nil {
+		t.Fatalf("baseline: %v", err)
+	}
+	src.dataSafety.Raw = &gplay.RawData{Node: []interface{}{"page", 2.0}}
+	if events, err := m.CheckDataSafety(ctx, "com.example"); err != nil || len(events) != 0 {
+		t.Fatalf("raw data change alone should not emit: got %+v, %v", events, err)
+	}
+
+	records, _ := store.List(ctx, "com.example", snapshot.Query{Kind: snapshot.KindDataSafety})
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	if ds, err := records[0].DataSafety(); err != nil || ds.Raw != nil {
+		t.Errorf("stored raw data: %+v, %v", ds, err)
+	}
+}
+
+func TestSnapshotsOnlyOnChange(t *testing.T) {
+	ctx := context.Background()
+	store := snapshot.NewMemoryStore()
+	src := &fakeSource{
+		app:         &gplay.App{AppID: "com.example", Version: "1.0"},
+		permissions: []gplay.Permission{{Type: "Camera", Permission: "take pictures", Raw: &gplay.RawData{Node: 1.0}}},
+	}
+	m := newTestMonitor(t, src, store)
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.CheckApp(ctx, "com.example"); err != nil {
+			t.Fatalf("CheckApp: %v", err)
+		}
+		if events, err := m.CheckPermissions(ctx, "com.example"); err != nil || len(events) != 0 {
+			t.Fatalf("CheckPermissions: got %+v, %v", events, err)
+		}
+		src.permissions[0].Raw = &gplay.RawData{Node: 2.0} // Raw data alone isn't a change
+	}
+	src.app.Version = "1.1"
+	if _, err := m.CheckApp(ctx, "com.example"); err != nil {
+		t.Fatalf("CheckApp: %v", err)
+	}
+
+	for kind, want := range map[snapshot.Kind]int{snapshot.KindApp: 2, snapshot.KindPermissions: 1} {
+		records, err := store.List(ctx, "com.example", snapshot.Query{Kind: kind})
+		if err != nil || len(records) != want {
+			t.Errorf("%s: got %d records, want %d (%v)", kind, len(records), want, err)
+		}
+	}
+	records, _ := store.List(ctx, "com.example", snapshot.Query{Kind: snapshot.KindPermissions})
+	if perms, err := records[0].Permissions(); err != nil || perms[0].Raw != nil {
+		t.Errorf("stored raw data: %+v, %v", perms, err)
+	}
+}
@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kryuchenko/google-play-scraper/snapshot"
+)
+
+// deliver passes e to the handler and posts it to every webhook, returning
+// the webhooks that didn't receive it
+func (m *Monitor) deliver(ctx context.Context, e Event) []string {
+	if m.cfg.Handler != nil {
+		m.cfg.Handler(e)
+	}
+	return m.broadcast(ctx, e, m.cfg.Webhooks)
+}
+
+// broadcast posts e to urls and returns the ones that failed
+func (m *Monitor) broadcast(ctx context.Context, e Event, urls []string) []string {
+	if len(urls) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		m.reportError(fmt.Errorf("encode event: %w", err))
+		return nil // Retrying wouldn't help
+	}
+	var failed []string
+	for _, url := range urls {
+		if err := m.post(ctx, url, body); err != nil {
+			failed = append(failed, url)
+			if ctx.Err() == nil {
+				m.reportError(fmt.Errorf("webhook %s: %w", url, err))
+			}
+		}
+	}
+	return failed
+}
+
+// kindPending stores an app's queue of events that webhooks haven't received.
+// Each record replaces the queue before it.
+const kindPending snapshot.Kind = "pending_events"
+
+// pendingEvent is a queued event and the webhooks that still need it
+type pendingEvent struct {
+	Event    Event    `json:"event"`
+	Webhooks []string `json:"webhooks"`
+}
+
+// pending returns the app's queued events
+func (m *Monitor) pending(ctx context.Context, appID string) ([]pendingEvent, error) {
+	rec, err := snapshot.Latest(ctx, m.cfg.Store, appID, kindPending)
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	var queue []pendingEvent
+	if err := json.Unmarshal(rec.Data, &queue); err != nil {
+		return nil, fmt.Errorf("decode pending events: %w", err)
+	}
+	return queue, nil
+}
+
+func (m *Monitor) savePending(ctx context.Context, appID string, queue []pendingEvent) error {
+	rec, err := snapshot.NewRecord(appID, kindPending, m.now(), queue)
+	if err != nil {
+		return err
+	}
+	return m.cfg.Store.Append(ctx, rec)
+}
+
+// addPending queues events for the next poll
+func (m *Monitor) addPending(ctx context.Context, appID string, events []pendingEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	queue, err := m.pending(ctx, appID)
+	if err != nil {
+		return err
+	}
+	return m.savePending(ctx, appID, append(queue, events...))
+}
+
+// retryPending posts the app's queued events to the webhooks that missed
+// them, keeping those that fail again
+func (m *Monitor) retryPending(ctx context.Context, appID string) error {
+	m.pendingMu.Lock()
+	defer m.pendingMu.Unlock()
+	queue, err := m.pending(ctx, appID)
+	if err != nil || len(queue) == 0 {
+		return err
+	}
+	var remaining []pendingEvent
+	for _, p := range queue {
+		if urls := m.broadcast(ctx, p.Event, p.Webhooks); len(urls) > 0 {
+			remaining = append(remaining, pendingEvent{Event: p.Event, Webhooks: urls})
+		}
+	}
+	return m.savePending(ctx, appID, remaining)
+}
+
+func (m *Monitor) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+	return nil
+}
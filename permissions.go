@@ -3,14 +3,17 @@ package googleplayscraper
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/url"
+
+	"github.com/kryuchenko/google-play-scraper/batchexecute"
 )
 
 // Permission represents an app permission
 type Permission struct {
-	Type       string `json:"type"`
-	Permission string `json:"permission"`
+	Type       string   `json:"type"`
+	Permission string   `json:"permission"`
+	Raw        *RawData `json:"raw,omitempty"` // Set with WithRawData
 }
 
 // PermissionsOptions configures the permissions request
@@ -34,57 +37,94 @@ func (c *Client) Permissions(ctx context.Context, opts PermissionsOptions) ([]Pe
 		opts.Country = "us"
 	}
 
-	reqURL := fmt.Sprintf("%s/_/PlayStoreUi/data/batchexecute?rpcids=xdSrCf&hl=%s&gl=%s",
-		BaseURL, opts.Lang, opts.Country)
-
-	body := fmt.Sprintf(`f.req=%%5B%%5B%%5B%%22xdSrCf%%22%%2C%%22%%5B%%5Bnull%%2C%%5B%%5C%%22%s%%5C%%22%%2C7%%5D%%2C%%5B%%5D%%5D%%5D%%22%%2Cnull%%2C%%221%%22%%5D%%5D%%5D`,
-		url.QueryEscape(opts.AppID))
-
-	respBody, err := c.post(ctx, reqURL, "application/x-www-form-urlencoded;charset=UTF-8", body)
+	respBody, err := c.rpc(ctx, opts.Lang, opts.Country, batchexecute.Call{RPCID: "xdSrCf", Args: permissionsArgs(opts.AppID)})
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	return parsePermissionsResponse(respBody, opts.Short)
+	rep := c.newReport(EndpointPermissions, "xdSrCf")
+	perms, err := parsePermissionsResponse(respBody, opts.Short, rep)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.sendReport(rep, len(perms)); err != nil {
+		return nil, err
+	}
+	return c.rawPermissions(perms), nil
 }
 
-func parsePermissionsResponse(body []byte, short bool) ([]Permission, error) {
-	// Skip the )]}'  prefix
-	start := 0
-	for i := range body {
-		if body[i] == '\n' {
-			start = i + 1
-			break
-		}
+// PermissionsBatch fetches permissions for several apps in a single request.
+// opts.AppID is ignored. Apps whose permissions could not be read are left
+// out of the map and reported together in the returned error.
+func (c *Client) PermissionsBatch(ctx context.Context, appIDs []string, opts PermissionsOptions) (map[string][]Permission, error) {
+	if len(appIDs) == 0 {
+		return nil, fmt.Errorf("at least one appID is required")
 	}
 
-	if start >= len(body) {
-		return nil, fmt.Errorf("invalid response")
+	if opts.Lang == "" {
+		opts.Lang = "en"
+	}
+	if opts.Country == "" {
+		opts.Country = "us"
 	}
 
-	var outer [][]interface{}
-	if err := json.Unmarshal(body[start:], &outer); err != nil {
-		return nil, err
+	args := make([]any, len(appIDs))
+	for i, appID := range appIDs {
+		args[i] = permissionsArgs(appID)
 	}
+	calls := batchexecute.Batch("xdSrCf", args...)
 
-	if len(outer) == 0 || len(outer[0]) < 3 {
-		return nil, nil
+	respBody, err := c.rpc(ctx, opts.Lang, opts.Country, calls...)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	resp, err := batchexecute.Decode(respBody)
+	if err != nil {
+		return nil, err
 	}
 
-	dataStr, ok := outer[0][2].(string)
-	if !ok {
-		return nil, nil
+	result := make(map[string][]Permission, len(appIDs))
+	var errs []error
+	for i, call := range calls {
+		raw, err := resp.Get(call.RPCID, call.ID)
+		var data []interface{}
+		if err == nil {
+			err = json.Unmarshal(raw, &data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("permissions for %s: %w", appIDs[i], err))
+			continue
+		}
+		rep := c.newReport(EndpointPermissions, "xdSrCf")
+		perms := extractPermissions(data, opts.Short, rep)
+		if err := c.sendReport(rep, len(perms)); err != nil {
+			errs = append(errs, fmt.Errorf("permissions for %s: %w", appIDs[i], err))
+			continue
+		}
+		result[appIDs[i]] = c.rawPermissions(perms)
 	}
 
-	var data []interface{}
-	if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
+	return result, errors.Join(errs...)
+}
+
+func permissionsArgs(appID string) []any {
+	return []any{[]any{nil, []any{appID, 7}, []any{}}}
+}
+
+func parsePermissionsResponse(body []byte, short bool, rep *ParseReport) ([]Permission, error) {
+	data, err := rpcData(body, "xdSrCf")
+	if err != nil {
+		// An app without a permissions envelope has no permissions to report
+		if noRPCData(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
-	if data == nil {
-		return nil, nil
-	}
+	return extractPermissions(data, short, rep), nil
+}
 
+func extractPermissions(data []interface{}, short bool, rep *ParseReport) []Permission {
 	var permissions []Permission
 
 	// Process common permissions (index 0) and other permissions (index 1)
@@ -95,6 +135,9 @@ func parsePermissionsResponse(body []byte, short bool) ([]Permission, error) {
 
 		typeData, ok := data[permType].([]interface{})
 		if !ok {
+			if data[permType] != nil {
+				rep.mismatch(fmt.Sprintf("xdSrCf[%d]", permType), "skipped: permission groups not found")
+			}
 			continue
 		}
 
@@ -103,9 +146,10 @@ func parsePermissionsResponse(body []byte, short bool) ([]Permission, error) {
 			typeName = "Other"
 		}
 
-		for _, group := range typeData {
+		for gi, group := range typeData {
 			groupArr, ok := group.([]interface{})
 			if !ok || len(groupArr) < 3 {
+				rep.mismatch(fmt.Sprintf("xdSrCf[%d][%d]", permType, gi), "skipped: not a permission group")
 				continue
 			}
 
@@ -118,25 +162,26 @@ func parsePermissionsResponse(body []byte, short bool) ([]Permission, error) {
 			// Permissions at [2]
 			perms, ok := groupArr[2].([]interface{})
 			if !ok {
+				rep.mismatch(fmt.Sprintf("xdSrCf[%d][%d][2]", permType, gi), "skipped: permissions not found")
 				continue
 			}
 
-			for _, perm := range perms {
+			for pi, perm := range perms {
 				permArr, ok := perm.([]interface{})
 				if !ok || len(permArr) < 2 {
+					rep.mismatch(fmt.Sprintf("xdSrCf[%d][%d][2][%d]", permType, gi, pi), "skipped: not a permission")
 					continue
 				}
 
-				permName := toString(permArr[1])
-				if permName != "" {
-					permissions = append(permissions, Permission{
-						Type:       groupType,
-						Permission: permName,
-					})
+				rep.at("xdSrCf[%d][%d][2][%d]", permType, gi, pi)
+				p := Permission{Type: groupType, Raw: &RawData{RPC: data, Node: permArr}}
+				applyFields(&p, permArr, permissionFields, rep)
+				if p.Permission != "" {
+					permissions = append(permissions, p)
 				}
 			}
 		}
 	}
 
-	return permissions, nil
+	return permissions
 }
@@ -72,13 +72,13 @@ func TestPermissionsInstagram(t *testing.T) {
 
 func TestParsePermissionsResponse(t *testing.T) {
 	// Test with empty/invalid response
-	_, err := parsePermissionsResponse([]byte("invalid"), false)
+	_, err := parsePermissionsResponse([]byte("invalid"), false, nil)
 	if err == nil {
 		t.Error("Expected error for invalid response")
 	}
 
 	// Test with response that has no data after prefix skip
-	_, err = parsePermissionsResponse([]byte(")]}'\n"), false)
+	_, err = parsePermissionsResponse([]byte(")]}'\n"), false, nil)
 	if err != nil {
 		t.Logf("Got expected error: %v", err)
 	}
@@ -0,0 +1,380 @@
+package playtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"sort"
+	"strings"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+// set stores value at path inside node, growing nested arrays as needed,
+// and returns the updated node
+func set(node any, value any, path ...int) any {
+	if len(path) == 0 {
+		return value
+	}
+	arr, _ := node.([]any)
+	for len(arr) <= path[0] {
+		arr = append(arr, nil)
+	}
+	arr[path[0]] = set(arr[path[0]], value, path[1:]...)
+	return arr
+}
+
+// setString stores non-empty strings only, so empty fields stay absent as on real pages
+func setString(node any, value string, path ...int) any {
+	if value == "" {
+		return node
+	}
+	return set(node, value, path...)
+}
+
+// page is the set of AF_initDataCallback blocks in a page, plus visible text
+type page struct {
+	blocks map[string]any
+	text   string
+}
+
+func writePage(w http.ResponseWriter, p page) {
+	keys := make([]string, 0, len(p.blocks))
+	for key := range p.blocks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("<!doctype html><html><head>")
+	for _, key := range keys {
+		data, err := json.Marshal(p.blocks[key])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(&b, "<script nonce=\"playtest\">AF_initDataCallback({key: '%s', hash: '1', data:%s, sideChannel: {}});</script>", key, data)
+	}
+	b.WriteString("</head><body>")
+	b.WriteString(p.text)
+	b.WriteString("</body></html>")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+// imageEntry encodes an image as [null, null, [width, height], [null, null, url]]
+func imageEntry(img gplay.Image) any {
+	var entry any
+	entry = set(entry, img.URL, 3, 2)
+	if img.Width > 0 || img.Height > 0 {
+		entry = set(entry, []any{img.Width, img.Height}, 2)
+	}
+	return entry
+}
+
+// micros converts a price to the millionths Google Play uses
+func micros(price float64) int64 {
+	return int64(price*1000000 + 0.5)
+}
+
+// notices shown on listings that can't be installed
+var unavailableText = map[gplay.Availability]string{
+	gplay.AvailabilityRegionRestricted: "This app isn't available in your country",
+	gplay.AvailabilityIncompatible:     "Your device isn't compatible with this version",
+}
+
+// detailsPage renders the app block at ds:5[1][2] and, when the app has
+// similar apps, the cluster list at ds:7[1][1]
+func detailsPage(app *gplay.App, hasSimilar bool) page {
+	var d any
+
+	d = setString(d, app.Title, 0, 0)
+	d = setString(d, app.ContentRating, 9, 0)
+	d = setString(d, app.Released, 10, 1, 0)
+
+	availability := app.Availability
+	if availability == "" {
+		availability = gplay.AvailabilityAvailable
+	}
+	switch availability {
+	case gplay.AvailabilityAvailable:
+		d = set(d, 2, 18, 0)
+	case gplay.AvailabilityPreRegistration:
+		d = set(d, 1, 18, 0)
+	}
+
+	if app.Installs != "" || app.MinInstalls > 0 {
+		d = set(d, []any{app.Installs, app.MinInstalls, app.MaxInstalls}, 13)
+	}
+
+	if app.Score > 0 || app.Ratings > 0 {
+		scoreText := app.ScoreText
+		if scoreText == "" {
+			scoreText = fmt.Sprintf("%.1f", app.Score)
+		}
+		histogram := make([]any, 5)
+		for i := range histogram {
+			histogram[i] = []any{5 - i, app.Histogram[4-i]} // 5 stars first
+		}
+		d = set(d, []any{
+			[]any{scoreText, app.Score},
+			histogram,
+			[]any{nil, app.Ratings},
+			[]any{nil, app.Reviews},
+		}, 51)
+	}
+
+	if !app.Free || app.Price > 0 || app.Currency != "" {
+		d = set(d, []any{micros(app.Price), app.Currency, app.PriceText}, 57, 0, 0, 0, 0, 1, 0)
+	}
+
+	d = setString(d, app.Developer, 68, 0)
+	d = setString(d, app.DeveloperID, 68, 1, 4, 2)
+	d = setString(d, app.DeveloperWebsite, 69, 0, 5, 2)
+	d = setString(d, app.DeveloperEmail, 69, 1, 0)
+	d = setString(d, app.DeveloperAddress, 69, 2, 0)
+
+	description := app.DescriptionHTML
+	if description == "" {
+		description = strings.ReplaceAll(app.Description, "\n", "<br>")
+	}
+	d = setString(d, description, 72, 0, 1)
+	d = setString(d, app.Summary, 73, 0, 1)
+
+	d = setString(d, app.Genre, 79, 0, 0, 0)
+	d = setString(d, app.GenreID, 79, 0, 0, 2)
+
+	media := mediaFor(app)
+	if shots := media.Screenshots[gplay.FormFactorPhone]; len(shots) > 0 {
+		entries := make([]any, len(shots))
+		for i, img := range shots {
+			entries[i] = imageEntry(img)
+		}
+		d = set(d, entries, 78, 0)
+	}
+	if media.Icon.URL != "" {
+		d = set(d, imageEntry(media.Icon), 95, 0)
+	}
+	if media.HeaderImage.URL != "" {
+		d = set(d, imageEntry(media.HeaderImage), 96, 0)
+	}
+	d = setString(d, app.PrivacyPolicy, 99, 0, 5, 2)
+	d = setString(d, media.Video, 100, 0, 0, 3, 2)
+	if media.VideoImage.URL != "" {
+		d = set(d, imageEntry(media.VideoImage), 100, 1, 0)
+	}
+
+	d = setString(d, app.Version, 140, 0, 0, 0)
+	d = setString(d, app.AndroidVersion, 140, 1, 1, 0, 0, 1)
+	if app.Updated > 0 {
+		d = set(d, app.Updated, 145, 0, 1, 0)
+	}
+
+	p := page{blocks: map[string]any{"ds:5": set(nil, d, 1, 2)}, text: unavailableText[availability]}
+	if hasSimilar {
+		var cluster any
+		cluster = set(cluster, "Similar apps", 21, 1, 0)
+		cluster = set(cluster, "/store/apps/collection/cluster?gsr=similar:"+app.AppID, 21, 1, 2, 4, 2)
+		p.blocks["ds:7"] = set(nil, []any{cluster}, 1, 1)
+	}
+	return p
+}
+
+// mediaFor fills Media from the flat App fields where it is not set
+func mediaFor(app *gplay.App) gplay.Media {
+	media := app.Media
+	if media.Icon.URL == "" {
+		media.Icon.URL = app.Icon
+	}
+	if media.HeaderImage.URL == "" {
+		media.HeaderImage.URL = app.HeaderImage
+	}
+	if media.Video == "" {
+		media.Video = app.Video
+	}
+	if media.VideoImage.URL == "" {
+		media.VideoImage.URL = app.VideoImage
+	}
+	if len(media.Screenshots[gplay.FormFactorPhone]) == 0 && len(app.Screenshots) > 0 {
+		shots := make(map[gplay.FormFactor][]gplay.Image, len(media.Screenshots)+1)
+		for ff, images := range media.Screenshots {
+			shots[ff] = images
+		}
+		for _, url := range app.Screenshots {
+			shots[gplay.FormFactorPhone] = append(shots[gplay.FormFactorPhone], gplay.Image{URL: url})
+		}
+		media.Screenshots = shots
+	}
+	return media
+}
+
+// cardEntry encodes an app as a card on search, list, developer and cluster pages
+func cardEntry(app gplay.App) any {
+	var c any
+	c = set(c, []any{app.AppID, 7}, 0)
+	c = setString(c, app.Icon, 1, 3, 2)
+	c = set(c, app.Title, 3)
+	if app.Score > 0 {
+		c = set(c, []any{fmt.Sprintf("%.1f", app.Score), app.Score}, 4)
+	}
+	if !app.Free || app.Price > 0 {
+		c = set(c, []any{nil, []any{[]any{micros(app.Price), app.Currency}}}, 8)
+	}
+	c = setString(c, app.Genre, 5)
+	c = setString(c, app.Installs, 6)
+	if app.Summary != "" {
+		c = set(c, []any{nil, app.Summary}, 13)
+	}
+	c = setString(c, app.Developer, 14)
+	if app.DeveloperID != "" {
+		c = set(c, "/store/apps/dev?id="+app.DeveloperID, 15, 4, 2)
+	}
+	return c
+}
+
+// searchPage renders the sections of the first results page at ds:4[0][1]:
+// the results at [0][0] of the first section with the token at [0][3][0],
+// then the featured card at [23][0] and ad and related clusters as
+// [cards, [title]] at [20] and [21]
+func searchPage(featured, apps []gplay.App, token string, sections []searchSection) page {
+	cards := make([]any, len(apps))
+	for i, app := range apps {
+		cards[i] = cardEntry(app)
+	}
+	results := []any{cards}
+	if token != "" {
+		results = append(results, nil, nil, []any{token})
+	}
+	list := []any{[]any{results}}
+
+	for _, app := range featured {
+		list = append(list, set(nil, cardEntry(app), 23, 0))
+	}
+	for _, s := range sections {
+		if s.kind == gplay.SectionAds {
+			list = append(list, clusterSection(s, 20))
+		}
+	}
+	for _, s := range sections {
+		if s.kind != gplay.SectionAds {
+			list = append(list, clusterSection(s, 21))
+		}
+	}
+	return page{blocks: map[string]any{"ds:4": set(nil, list, 0, 1)}}
+}
+
+// clusterSection renders a search page cluster at index
+func clusterSection(s searchSection, index int) any {
+	cards := make([]any, len(s.apps))
+	for i, app := range s.apps {
+		cards[i] = cardEntry(app)
+	}
+	return set(nil, []any{cards, []any{s.title}}, index)
+}
+
+// chartPage renders top free, paid and grossing sections at ds:4[0][1][n][21][0]
+func chartPage(sections [][]gplay.App) page {
+	var ds4 any
+	for i, apps := range sections {
+		cards := make([]any, len(apps))
+		for j, app := range apps {
+			cards[j] = cardEntry(app)
+		}
+		ds4 = set(ds4, cards, 0, 1, i, 21, 0)
+	}
+	return page{blocks: map[string]any{"ds:4": ds4}}
+}
+
+// developerPage renders apps at ds:3[0][1][0][21][0] for numeric developer IDs,
+// or wrapped in an extra array at [22][0] for developer names
+func developerPage(apps []gplay.App, numeric bool) page {
+	cards := make([]any, len(apps))
+	for i, app := range apps {
+		if numeric {
+			cards[i] = cardEntry(app)
+		} else {
+			cards[i] = []any{cardEntry(app)}
+		}
+	}
+	index := 22
+	if numeric {
+		index = 21
+	}
+	return page{blocks: map[string]any{"ds:3": set(nil, cards, 0, 1, 0, index, 0)}}
+}
+
+// clusterPage renders a cluster's apps at ds:3[0][1][0][21][0]
+func clusterPage(apps []gplay.App) page {
+	return developerPage(apps, true)
+}
+
+// dataSafetyPage renders the section at ds:3[1][2][1][138]
+func dataSafetyPage(ds *gplay.DataSafety) page {
+	var section any
+	section = set(section, dataEntries(ds.SharedData), 4, 0, 0)
+	section = set(section, dataEntries(ds.CollectedData), 4, 1, 0)
+
+	practices := make([]any, len(ds.SecurityPractices))
+	for i, p := range ds.SecurityPractices {
+		practices[i] = []any{nil, p.Practice, []any{nil, p.Description}}
+	}
+	section = set(section, practices, 9, 2)
+
+	var ds3 any
+	ds3 = set(ds3, section, 1, 2, 1, 138)
+	ds3 = setString(ds3, ds.PrivacyPolicyURL, 1, 2, 1, 100, 0, 5, 2)
+	return page{blocks: map[string]any{"ds:3": ds3}}
+}
+
+// dataEntries groups entries by type as [[null, type], null, null, null, [[data, optional, purpose], ...]]
+func dataEntries(entries []gplay.DataSafetyEntry) []any {
+	var groups []any
+	index := make(map[string]int)
+	for _, e := range entries {
+		i, ok := index[e.Type]
+		if !ok {
+			i = len(groups)
+			index[e.Type] = i
+			groups = append(groups, []any{[]any{nil, e.Type}, nil, nil, nil, []any{}})
+		}
+		optional := 0
+		if e.Optional {
+			optional = 1
+		}
+		group := groups[i].([]any)
+		group[4] = append(group[4].([]any), []any{e.Data, optional, e.Purpose})
+	}
+	if groups == nil {
+		groups = []any{}
+	}
+	return groups
+}
+
+// serveImage answers asset requests with a small PNG whose colour depends on
+// the image path, ignoring any "=w100-h100" size suffix
+func serveImage(w http.ResponseWriter, r *http.Request) {
+	path, _, _ := strings.Cut(r.URL.Path, "=")
+	h := fnv.New32a()
+	h.Write([]byte(path))
+	shade := uint8(h.Sum32())
+
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range img.Pix {
+		img.Pix[i] = shade
+	}
+	img.SetGray(0, 0, color.Gray{Y: ^shade})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(buf.Bytes())
+}
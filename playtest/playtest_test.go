@@ -0,0 +1,435 @@
+package playtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+var ctx = context.Background()
+
+func exampleApp() gplay.App {
+	app := gplay.App{
+		AppID:            "com.example.notes",
+		Title:            "Example Notes",
+		Summary:          "Notes that sync",
+		Description:      "Write notes.\nSync them everywhere.",
+		Installs:         "1,000,000+",
+		MinInstalls:      1000000,
+		MaxInstalls:      1534210,
+		Score:            4.5,
+		ScoreText:        "4.5",
+		Ratings:          1200,
+		Reviews:          300,
+		Histogram:        [5]int{10, 20, 30, 140, 1000},
+		Price:            2.99,
+		PriceText:        "$2.99",
+		Currency:         "USD",
+		Developer:        "Example Inc",
+		DeveloperID:      "5700313618786177705",
+		DeveloperEmail:   "support@example.com",
+		DeveloperWebsite: "https://example.com",
+		Genre:            "Productivity",
+		GenreID:          "PRODUCTIVITY",
+		Icon:             "https://play-lh.googleusercontent.com/icon",
+		HeaderImage:      "https://play-lh.googleusercontent.com/header",
+		Screenshots:      []string{"https://play-lh.googleusercontent.com/shot1", "https://play-lh.googleusercontent.com/shot2"},
+		ContentRating:    "Everyone",
+		Released:         "Jan 2, 2020",
+		Updated:          1700000000,
+		Version:          "2.1.0",
+		AndroidVersion:   "8.0",
+		PrivacyPolicy:    "https://example.com/privacy",
+	}
+	return app
+}
+
+func TestApp(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	want := exampleApp()
+	srv.AddApp(want)
+
+	app, err := srv.Client().App(ctx, want.AppID, gplay.AppOptions{})
+	if err != nil {
+		t.Fatalf("App: %v", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"Title", app.Title, want.Title},
+		{"Summary", app.Summary, want.Summary},
+		{"Description", app.Description, want.Description},
+		{"MinInstalls", app.MinInstalls, want.MinInstalls},
+		{"Score", app.Score, want.Score},
+		{"Ratings", app.Ratings, want.Ratings},
+		{"Histogram", app.Histogram, want.Histogram},
+		{"Price", app.Price, want.Price},
+		{"Currency", app.Currency, want.Currency},
+		{"Free", app.Free, false},
+		{"Developer", app.Developer, want.Developer},
+		{"DeveloperID", app.DeveloperID, want.DeveloperID},
+		{"DeveloperEmail", app.DeveloperEmail, want.DeveloperEmail},
+		{"Genre", app.Genre, want.Genre},
+		{"Icon", app.Icon, want.Icon},
+		{"Screenshots", len(app.Screenshots), 2},
+		{"Version", app.Version, want.Version},
+		{"AndroidVersion", app.AndroidVersion, want.AndroidVersion},
+		{"Updated", app.Updated, want.Updated},
+		{"PrivacyPolicy", app.PrivacyPolicy, want.PrivacyPolicy},
+		{"Availability", app.Availability, gplay.AvailabilityAvailable},
+	}
+	for _, c := range checks {
+		if fmt.Sprint(c.got) != fmt.Sprint(c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestAppAvailability(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddApp(gplay.App{AppID: "com.example.soon", Title: "Soon", Free: true,
+		Availability: gplay.AvailabilityPreRegistration})
+	srv.AddApp(gplay.App{AppID: "com.example.local", Title: "Local", Free: true,
+		Availability: gplay.AvailabilityRegionRestricted})
+	client := srv.Client()
+
+	app, err := client.App(ctx, "com.example.soon", gplay.AppOptions{})
+	if err != nil || app.Availability != gplay.AvailabilityPreRegistration || !app.PreRegister {
+		t.Errorf("pre-registration: %+v, %v", app, err)
+	}
+
+	_, err = client.App(ctx, "com.example.local", gplay.AppOptions{})
+	if !errors.Is(err, gplay.ErrRegionRestricted) {
+		t.Errorf("region restricted: got %v", err)
+	}
+
+	_, err = client.App(ctx, "com.example.missing", gplay.AppOptions{})
+	if !errors.Is(err, gplay.ErrNotFound) {
+		t.Errorf("missing app: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestReviews(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 45; i++ {
+		srv.AddReviews("com.example.notes", gplay.Review{
+			ID:       fmt.Sprintf("gp:%02d", i),
+			UserName: "user",
+			Score:    i%5 + 1,
+			Text:     "review",
+			Date:     base.Add(time.Duration(i) * time.Hour),
+			ThumbsUp: i,
+		})
+	}
+	client := srv.Client()
+
+	first, err := client.Reviews(ctx, "com.example.notes", gplay.ReviewOptions{Count: 20})
+	if err != nil {
+		t.Fatalf("Reviews: %v", err)
+	}
+	if len(first.Reviews) != 20 || first.NextToken == "" {
+		t.Fatalf("first page: %d reviews, token %q", len(first.Reviews), first.NextToken)
+	}
+	if first.Reviews[0].ID != "gp:44" || !first.Reviews[0].Date.Equal(base.Add(44*time.Hour)) {
+		t.Errorf("newest first: got %s at %v", first.Reviews[0].ID, first.Reviews[0].Date)
+	}
+
+	all, err := client.ReviewsAll(ctx, "com.example.notes", gplay.ReviewOptions{Count: 100})
+	if err != nil || len(all) != 45 {
+		t.Errorf("ReviewsAll: %d reviews, %v", len(all), err)
+	}
+
+	fives, err := client.Reviews(ctx, "com.example.notes", gplay.ReviewOptions{Count: 100, FilterScore: 5})
+	if err != nil || len(fives.Reviews) != 9 {
+		t.Errorf("filtered: %v, %v", fives, err)
+	}
+}
+
+func TestSearchPaginates(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	for i := 0; i < 30; i++ {
+		srv.AddApp(gplay.App{AppID: fmt.Sprintf("com.example.game%d", i), Title: fmt.Sprintf("Game %d", i), Free: i%2 == 0, Price: float64(i % 2), Currency: "USD"})
+	}
+
+	results, err := srv.Client().Search(ctx, gplay.SearchOptions{Term: "game", Num: 25})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 25 || results[24].AppID != "com.example.game24" {
+		t.Fatalf("got %d results", len(results))
+	}
+	if results[21].Price != 1 || results[21].Free {
+		t.Errorf("second page result: %+v", results[21])
+	}
+
+	var rpcs int
+	for _, r := range srv.Requests() {
+		if r == "POST batchexecute:qnKhOb" {
+			rpcs++
+		}
+	}
+	if rpcs != 1 {
+		t.Errorf("expected one pagination RPC, requests: %v", srv.Requests())
+	}
+
+	paid, err := srv.Client().Search(ctx, gplay.SearchOptions{Term: "game", Price: "paid", Num: 50})
+	if err != nil || len(paid) != 15 {
+		t.Errorf("paid search: %d results, %v", len(paid), err)
+	}
+}
+
+func TestSearchFullDetailFields(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	app := exampleApp()
+	srv.AddApp(app)
+
+	results, err := srv.Client().Search(ctx, gplay.SearchOptions{Term: "notes", FullDetail: true})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Search: %+v, %v", results, err)
+	}
+	if r := results[0]; r.Installs != app.Installs || r.Genre != app.Genre {
+		t.Errorf("Installs %q, Genre %q; want %q, %q", r.Installs, r.Genre, app.Installs, app.Genre)
+	}
+}
+
+func TestSearchBeyond250(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	for i := 0; i < 310; i++ {
+		srv.AddApp(gplay.App{AppID: fmt.Sprintf("com.example.tool%d", i), Title: fmt.Sprintf("Tool %d", i), Free: true})
+	}
+	client := srv.Client()
+
+	results, err := client.Search(ctx, gplay.SearchOptions{Term: "tool", Num: 300})
+	if err != nil || len(results) != 300 || results[299].AppID != "com.example.tool299" {
+		t.Errorf("Num 300: %d results, %v", len(results), err)
+	}
+
+	all, err := client.Search(ctx, gplay.SearchOptions{Term: "tool", All: true})
+	if err != nil || len(all) != 310 {
+		t.Errorf("All: %d results, %v", len(all), err)
+	}
+}
+
+func TestSearchSections(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	for _, app := range []gplay.App{
+		{AppID: "com.example.maps", Title: "Maps", Free: true},
+		{AppID: "com.example.maps.lite", Title: "Maps Lite", Free: true},
+		{AppID: "com.example.ride", Title: "Ride", Free: true},
+		{AppID: "com.example.compass", Title: "Compass", Free: true},
+	} {
+		srv.AddApp(app)
+	}
+	srv.SetFeatured("maps", "com.example.maps")
+	srv.AddSearchSection("maps", gplay.SectionAds, "Sponsored", "com.example.ride")
+	srv.AddSearchSection("maps", gplay.SectionRelated, "You might also like", "com.example.compass")
+	client := srv.Client()
+
+	results, err := client.Search(ctx, gplay.SearchOptions{Term: "maps"})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Search: %+v, %v", results, err)
+	}
+	if results[0].AppID != "com.example.maps" || results[0].Featured || results[1].AppID != "com.example.maps.lite" {
+		t.Errorf("Search: %+v", results)
+	}
+
+	page, err := client.SearchPage(ctx, gplay.SearchOptions{Term: "maps"})
+	if err != nil || len(page.Sections) != 3 {
+		t.Fatalf("SearchPage: %+v, %v", page, err)
+	}
+	if !page.Sections[0].Results[0].Featured {
+		t.Errorf("featured card: %+v", page.Sections[0])
+	}
+	for i, want := range []struct {
+		kind  gplay.SectionKind
+		title string
+		appID string
+	}{
+		{gplay.SectionFeatured, "", "com.example.maps"},
+		{gplay.SectionAds, "Sponsored", "com.example.ride"},
+		{gplay.SectionRelated, "You might also like", "com.example.compass"},
+	} {
+		s := page.Sections[i]
+		if s.Kind != want.kind || s.Title != want.title || len(s.Results) != 1 || s.Results[0].AppID != want.appID {
+			t.Errorf("section %d: %+v", i, s)
+		}
+	}
+}
+
+func TestSearchFirstPageFields(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	app := exampleApp()
+	srv.AddApp(app)
+
+	results, err := srv.Client(gplay.WithStrict()).Search(ctx, gplay.SearchOptions{Term: "notes"})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Search: %+v, %v", results, err)
+	}
+	r := results[0]
+	for _, f := range []struct {
+		name      string
+		got, want any
+	}{
+		{"Free", r.Free, false},
+		{"Price", r.Price, app.Price},
+		{"Currency", r.Currency, app.Currency},
+		{"Summary", r.Summary, app.Summary},
+		{"DeveloperID", r.DeveloperID, app.DeveloperID},
+		{"Installs", r.Installs, app.Installs},
+		{"Genre", r.Genre, app.Genre},
+	} {
+		if f.got != f.want {
+			t.Errorf("%s: got %v, want %v", f.name, f.got, f.want)
+		}
+	}
+}
+
+func TestListsAndClusters(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	app := exampleApp()
+	srv.AddApp(app)
+	srv.AddApp(gplay.App{AppID: "com.example.todo", Title: "Todo", Free: true, Developer: "Example Inc", DeveloperID: app.DeveloperID})
+	srv.SetTopChart(gplay.CollectionTopPaid, gplay.CategoryApplication, app.AppID)
+	srv.SetSimilar(app.AppID, "com.example.todo")
+	client := srv.Client()
+
+	top, err := client.List(ctx, gplay.ListOptions{Collection: gplay.CollectionTopPaid, Category: gplay.CategoryApplication})
+	if err != nil || len(top) != 1 || top[0].AppID != app.AppID || top[0].Price != app.Price {
+		t.Errorf("List: %+v, %v", top, err)
+	}
+
+	for _, devID := range []string{app.DeveloperID, app.Developer} {
+		apps, err := client.Developer(ctx, gplay.DeveloperOptions{DevID: devID})
+		if err != nil || len(apps) != 2 {
+			t.Errorf("Developer(%q): %d apps, %v", devID, len(apps), err)
+		}
+	}
+
+	similar, err := client.Similar(ctx, gplay.SimilarOptions{AppID: app.AppID})
+	if err != nil || len(similar) != 1 || similar[0].AppID != "com.example.todo" {
+		t.Errorf("Similar: %+v, %v", similar, err)
+	}
+}
+
+func TestAnyPackagePrefix(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	ids := []string{"ru.yandex.taxi", "jp.naver.line.android", "tv.twitch.android.app"}
+	for _, id := range ids {
+		srv.AddApp(gplay.App{AppID: id, Title: "Chat " + id, Icon: "https://play-lh.googleusercontent.com/" + id, Summary: "Talk to friends", Genre: "Communication", Free: true, Developer: "Far Labs", DeveloperID: "4242"})
+	}
+	srv.SetSimilar(ids[0], ids[1:]...)
+	client := srv.Client(gplay.WithStrict())
+
+	search, err := client.Search(ctx, gplay.SearchOptions{Term: "chat"})
+	if err != nil || len(search) != len(ids) {
+		t.Errorf("Search: %+v, %v", search, err)
+	}
+	for _, devID := range []string{"4242", "Far Labs"} {
+		apps, err := client.Developer(ctx, gplay.DeveloperOptions{DevID: devID})
+		if err != nil || len(apps) != len(ids) {
+			t.Errorf("Developer(%q): %+v, %v", devID, apps, err)
+		}
+	}
+	similar, err := client.Similar(ctx, gplay.SimilarOptions{AppID: ids[0]})
+	if err != nil || len(similar) != 2 || similar[1].AppID != "tv.twitch.android.app" {
+		t.Errorf("Similar: %+v, %v", similar, err)
+	}
+}
+
+func TestRPCs(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddApp(exampleApp())
+	srv.SetPermissions("com.example.notes",
+		gplay.Permission{Type: "Location", Permission: "precise location"},
+		gplay.Permission{Type: "Location", Permission: "approximate location"},
+		gplay.Permission{Type: "Other", Permission: "full network access"},
+	)
+	srv.SetSuggestions("maps", "maps offline", "maps navigation")
+	client := srv.Client()
+
+	perms, err := client.Permissions(ctx, gplay.PermissionsOptions{AppID: "com.example.notes"})
+	if err != nil || len(perms) != 3 || perms[1].Type != "Location" || perms[2].Permission != "full network access" {
+		t.Errorf("Permissions: %+v, %v", perms, err)
+	}
+
+	srv.SetPermissions("com.example.todo", gplay.Permission{Type: "Camera", Permission: "take pictures"})
+	before := len(srv.Requests())
+	batch, err := client.PermissionsBatch(ctx, []string{"com.example.notes", "com.example.todo"}, gplay.PermissionsOptions{})
+	if err != nil || len(batch["com.example.notes"]) != 3 || batch["com.example.todo"][0].Permission != "take pictures" {
+		t.Errorf("PermissionsBatch: %+v, %v", batch, err)
+	}
+	if got := srv.Requests()[before:]; len(got) != 2 {
+		t.Errorf("expected two xdSrCf calls, got %v", got)
+	}
+
+	suggestions, err := client.Suggest(ctx, gplay.SuggestOptions{Term: "maps"})
+	if err != nil || len(suggestions) != 2 || suggestions[0] != "maps offline" {
+		t.Errorf("Suggest: %v, %v", suggestions, err)
+	}
+	suggestions, err = client.Suggest(ctx, gplay.SuggestOptions{Term: "exam"})
+	if err != nil || len(suggestions) != 1 || suggestions[0] != "Example Notes" {
+		t.Errorf("Suggest from titles: %v, %v", suggestions, err)
+	}
+}
+
+func TestDataSafety(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddApp(exampleApp())
+	srv.SetDataSafety("com.example.notes", gplay.DataSafety{
+		CollectedData: []gplay.DataSafetyEntry{
+			{Type: "Personal info", Data: "Email address", Purpose: "Account management"},
+			{Type: "Personal info", Data: "Name", Optional: true, Purpose: "Personalization"},
+		},
+		SecurityPractices: []gplay.SecurityPractice{{Practice: "Data is encrypted in transit", Description: "Your data is transferred over a secure connection"}},
+		PrivacyPolicyURL:  "https://example.com/privacy",
+	})
+
+	ds, err := srv.Client().DataSafety(ctx, gplay.DataSafetyOptions{AppID: "com.example.notes"})
+	if err != nil {
+		t.Fatalf("DataSafety: %v", err)
+	}
+	if len(ds.CollectedData) != 2 || !ds.CollectedData[1].Optional || ds.CollectedData[0].Type != "Personal info" {
+		t.Errorf("collected data: %+v", ds.CollectedData)
+	}
+	if len(ds.SharedData) != 0 || len(ds.SecurityPractices) != 1 || ds.PrivacyPolicyURL != "https://example.com/privacy" {
+		t.Errorf("data safety: %+v", ds)
+	}
+}
+
+func TestAssets(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	client := srv.Client()
+
+	icon, contentType, err := client.FetchAsset(ctx, gplay.ImageURL("https://play-lh.googleusercontent.com/icon", gplay.ImageSize{Width: 64}))
+	if err != nil || contentType != "image/png" {
+		t.Fatalf("FetchAsset: %s, %v", contentType, err)
+	}
+	again, _, _ := client.FetchAsset(ctx, "https://play-lh.googleusercontent.com/icon=s0")
+	if string(icon) != string(again) {
+		t.Error("size suffix should not change the served image")
+	}
+	other, _, _ := client.FetchAsset(ctx, "https://play-lh.googleusercontent.com/header")
+	if string(icon) == string(other) {
+		t.Error("different images should differ")
+	}
+}
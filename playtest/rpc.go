@@ -0,0 +1,276 @@
+package playtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+	"github.com/kryuchenko/google-play-scraper/batchexecute"
+)
+
+func (s *Server) serveRPC(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.record("POST batchexecute")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	calls, err := batchexecute.ParseRequest(r.PostForm.Get("f.req"))
+	if err != nil {
+		s.record("POST batchexecute")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results := make([]batchexecute.Result, len(calls))
+	for i, call := range calls {
+		s.record("POST batchexecute:" + call.RPCID)
+
+		var data any
+		switch call.RPCID {
+		case "oCPfdb":
+			data = s.rpcReviews(call.Args)
+		case "qnKhOb":
+			data = s.rpcSearchPage(call.Args)
+		case "xdSrCf":
+			data = s.rpcPermissions(call.Args)
+		case "IJ4APc":
+			data = s.rpcSuggest(call.Args)
+		default:
+			http.Error(w, "unknown rpc "+call.RPCID, http.StatusBadRequest)
+			return
+		}
+
+		raw, err := json.Marshal(data)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		results[i] = batchexecute.Result{RPCID: call.RPCID, ID: call.ID, Data: raw}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	batchexecute.WriteResponse(w, results...)
+}
+
+// arg reads a value from the decoded payload, or nil if the path is missing
+func arg(node any, path ...int) any {
+	for _, i := range path {
+		arr, ok := node.([]any)
+		if !ok || i >= len(arr) {
+			return nil
+		}
+		node = arr[i]
+	}
+	return node
+}
+
+func argString(node any, path ...int) string {
+	s, _ := arg(node, path...).(string)
+	return s
+}
+
+func argInt(node any, path ...int) int {
+	f, _ := arg(node, path...).(float64)
+	return int(f)
+}
+
+// encodeToken packs pagination state into an opaque token
+func encodeToken(fields ...string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(fields, "\x00")))
+}
+
+func decodeToken(token string) []string {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(string(data), "\x00")
+}
+
+// paginate returns pageSize items from offset and the next offset, or 0 at the end
+func paginate[T any](items []T, offset int) ([]T, int) {
+	return paginateN(items, offset, pageSize)
+}
+
+func paginateN[T any](items []T, offset, n int) ([]T, int) {
+	if offset >= len(items) {
+		return nil, 0
+	}
+	end := min(offset+n, len(items))
+	if end == len(items) {
+		return items[offset:end], 0
+	}
+	return items[offset:end], end
+}
+
+// rpcReviews answers [null,[2,sort,[count,null,token],null,[null,score]],[appID,7]]
+// with [[review...], [null, nextToken]]
+func (s *Server) rpcReviews(args any) any {
+	appID := argString(args, 2, 0)
+	sortBy := gplay.Sort(argInt(args, 1, 1))
+	count := argInt(args, 1, 2, 0)
+	token := argString(args, 1, 2, 2)
+	score := argInt(args, 1, 4, 1)
+
+	s.mu.Lock()
+	var reviews []gplay.Review
+	for _, r := range s.reviews[appID] {
+		if score == 0 || r.Score == score {
+			reviews = append(reviews, r)
+		}
+	}
+	s.mu.Unlock()
+
+	sort.SliceStable(reviews, func(i, j int) bool {
+		switch sortBy {
+		case gplay.SortRating:
+			return reviews[i].Score > reviews[j].Score
+		case gplay.SortHelpfulness:
+			return reviews[i].ThumbsUp > reviews[j].ThumbsUp
+		}
+		return reviews[i].Date.After(reviews[j].Date)
+	})
+
+	offset := 0
+	if fields := decodeToken(token); len(fields) == 1 {
+		offset, _ = strconv.Atoi(fields[0])
+	}
+	if count <= 0 {
+		count = 150
+	}
+	page, next := paginateN(reviews, offset, count)
+
+	entries := make([]any, len(page))
+	for i, r := range page {
+		entries[i] = reviewEntry(r)
+	}
+	data := []any{entries}
+	if next > 0 {
+		data = append(data, []any{nil, encodeToken(strconv.Itoa(next))})
+	}
+	return data
+}
+
+// reviewEntry encodes [id, [user, [null,null,null,[null,null,image]]], score, null, text, [secs], thumbsUp, reply, null, null, version]
+func reviewEntry(r gplay.Review) any {
+	var e any
+	e = set(e, r.ID, 0)
+	e = set(e, r.UserName, 1, 0)
+	e = setString(e, r.UserImage, 1, 1, 3, 2)
+	e = set(e, r.Score, 2)
+	e = set(e, r.Text, 4)
+	if !r.Date.IsZero() {
+		e = set(e, []any{r.Date.Unix()}, 5)
+	}
+	e = set(e, r.ThumbsUp, 6)
+	if r.ReplyText != "" {
+		e = set(e, r.ReplyText, 7, 1)
+		if !r.ReplyDate.IsZero() {
+			e = set(e, []any{r.ReplyDate.Unix()}, 7, 2)
+		}
+	}
+	e = setString(e, r.Version, 10)
+	return e
+}
+
+// rpcSearchPage answers [[null,[...],[null,token]]] with [[[apps, ..., [null, nextToken]]]]
+func (s *Server) rpcSearchPage(args any) any {
+	fields := decodeToken(argString(args, 0, 2, 1))
+	if len(fields) != 3 {
+		return []any{}
+	}
+	offset, _ := strconv.Atoi(fields[2])
+	page, next := paginate(s.search(fields[0], fields[1]), offset)
+
+	entries := make([]any, len(page))
+	for i, app := range page {
+		entries[i] = searchEntry(app)
+	}
+	var result any
+	result = set(result, entries, 0, 0, 0)
+	if next > 0 {
+		result = set(result, encodeToken(fields[0], fields[1], strconv.Itoa(next)), 0, 0, 7, 1)
+	}
+	return result
+}
+
+// searchEntry encodes an app in the search pagination RPC layout
+func searchEntry(app gplay.App) any {
+	var e any
+	e = setString(e, app.Icon, 1, 1, 0, 3, 2)
+	e = set(e, app.Title, 2)
+	e = setString(e, app.Developer, 4, 0, 0, 0)
+	if app.DeveloperID != "" {
+		e = set(e, "/store/apps/dev?id="+app.DeveloperID, 4, 0, 0, 1, 4, 2)
+	}
+	e = setString(e, app.Summary, 4, 1, 1, 1, 1)
+	if app.Score > 0 {
+		e = set(e, []any{fmt.Sprintf("%.1f", app.Score), app.Score}, 6, 0, 2, 1)
+	}
+	if !app.Free || app.Price > 0 {
+		e = set(e, []any{micros(app.Price), app.Currency}, 7, 0, 3, 2, 1, 0)
+	}
+	e = set(e, "/store/apps/details?id="+app.AppID, 9, 4, 2)
+	e = set(e, []any{app.AppID, 7}, 12)
+	return e
+}
+
+// rpcPermissions answers [[null,[appID,7],[]]] with [commonGroups, otherGroups]
+func (s *Server) rpcPermissions(args any) any {
+	appID := argString(args, 0, 1, 0)
+
+	s.mu.Lock()
+	perms := s.permissions[appID]
+	s.mu.Unlock()
+
+	var common []any
+	var other []any
+	index := make(map[string]int)
+	for _, p := range perms {
+		if p.Type == "Other" || p.Type == "" {
+			other = append(other, []any{nil, p.Permission})
+			continue
+		}
+		i, ok := index[p.Type]
+		if !ok {
+			i = len(common)
+			index[p.Type] = i
+			common = append(common, []any{p.Type, nil, []any{}})
+		}
+		group := common[i].([]any)
+		group[2] = append(group[2].([]any), []any{nil, p.Permission})
+	}
+
+	data := []any{common, []any{}}
+	if len(other) > 0 {
+		data[1] = []any{[]any{"", nil, other}}
+	}
+	return data
+}
+
+// rpcSuggest answers [[null,[term],[10],[2],4]] with [[[suggestion], ...]]
+func (s *Server) rpcSuggest(args any) any {
+	term := strings.ToLower(argString(args, 0, 1, 0))
+
+	s.mu.Lock()
+	suggestions, ok := s.suggestions[term]
+	if !ok {
+		for _, id := range s.order {
+			if title := s.apps[id].Title; strings.HasPrefix(strings.ToLower(title), term) {
+				suggestions = append(suggestions, title)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	entries := make([]any, len(suggestions))
+	for i, suggestion := range suggestions {
+		entries[i] = []any{suggestion}
+	}
+	return []any{[]any{entries}}
+}
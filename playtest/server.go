@@ -0,0 +1,363 @@
+// Package playtest provides a fake Google Play server for offline tests.
+//
+// The server renders details, search, top chart, category, developer,
+// cluster and data safety pages as AF_initDataCallback blocks, and answers
+// the batchexecute RPCs for reviews (oCPfdb), search pagination (qnKhOb),
+// permissions (xdSrCf) and suggestions (IJ4APc). Pages are generated from
+// the App, Review, Permission and DataSafety values added to the server, so
+// a test states the data it expects to get back:
+//
+//	srv := playtest.NewServer()
+//	defer srv.Close()
+//	srv.AddApp(googleplayscraper.App{AppID: "com.example", Title: "Example"})
+//
+//	client := srv.Client()
+//	app, err := client.App(ctx, "com.example", googleplayscraper.AppOptions{})
+package playtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+// pageSize is the number of results per search page and RPC page
+const pageSize = 20
+
+// Server is a fake Google Play. Configure it with the Add and Set methods,
+// then talk to it through Client or Transport.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	apps        map[string]*gplay.App
+	order       []string // App IDs in insertion order, for search and developer pages
+	reviews     map[string][]gplay.Review
+	permissions map[string][]gplay.Permission
+	dataSafety  map[string]*gplay.DataSafety
+	similar     map[string][]string
+	charts      map[chartKey][]string
+	suggestions map[string][]string
+	featured    map[string]string
+	sections    map[string][]searchSection
+	requests    []string
+}
+
+// searchSection is an ad or related cluster on a search page
+type searchSection struct {
+	kind  gplay.SectionKind
+	title string
+	ids   []string
+	apps  []gplay.App // Looked up when a page is served
+}
+
+type chartKey struct {
+	collection gplay.Collection
+	category   gplay.Category
+}
+
+// NewServer starts a fake Google Play server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		apps:        make(map[string]*gplay.App),
+		reviews:     make(map[string][]gplay.Review),
+		permissions: make(map[string][]gplay.Permission),
+		dataSafety:  make(map[string]*gplay.DataSafety),
+		similar:     make(map[string][]string),
+		charts:      make(map[chartKey][]string),
+		suggestions: make(map[string][]string),
+		featured:    make(map[string]string),
+		sections:    make(map[string][]searchSection),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// AddApp adds or replaces an app. Its details page reflects every field the
+// scraper parses; Availability selects pre-registration and unavailable pages.
+func (s *Server) AddApp(app gplay.App) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.apps[app.AppID]; !ok {
+		s.order = append(s.order, app.AppID)
+	}
+	s.apps[app.AppID] = &app
+}
+
+// AddReviews appends reviews for an app. Dates are served at second precision.
+func (s *Server) AddReviews(appID string, reviews ...gplay.Review) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reviews[appID] = append(s.reviews[appID], reviews...)
+}
+
+// SetPermissions sets the permissions of an app. Permissions of type "Other"
+// are served in the uncategorised group.
+func (s *Server) SetPermissions(appID string, perms ...gplay.Permission) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.permissions[appID] = perms
+}
+
+// SetDataSafety sets the data safety section of an app
+func (s *Server) SetDataSafety(appID string, ds gplay.DataSafety) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dataSafety[appID] = &ds
+}
+
+// SetSimilar sets the apps listed in an app's "Similar apps" cluster
+func (s *Server) SetSimilar(appID string, similarIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.similar[appID] = similarIDs
+}
+
+// SetTopChart sets the apps in a top chart. Google Play serves APPLICATION and
+// GAME charts from the same page, which shows the APPLICATION charts.
+func (s *Server) SetTopChart(collection gplay.Collection, category gplay.Category, appIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.charts[chartKey{collection, category}] = appIDs
+}
+
+// SetSuggestions sets the suggestions for a term. Without them, Suggest
+// returns titles of added apps that start with the term.
+func (s *Server) SetSuggestions(term string, suggestions ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.suggestions[strings.ToLower(term)] = suggestions
+}
+
+// SetFeatured shows an app as the top result card above a term's results
+func (s *Server) SetFeatured(term, appID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.featured[strings.ToLower(term)] = appID
+}
+
+// AddSearchSection adds an ad or related cluster to a term's first search page
+func (s *Server) AddSearchSection(term string, kind gplay.SectionKind, title string, appIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	term = strings.ToLower(term)
+	s.sections[term] = append(s.sections[term], searchSection{kind: kind, title: title, ids: appIDs})
+}
+
+// Requests returns the requests served so far, as "GET /store/apps/details"
+// for pages and "POST batchexecute:oCPfdb" for RPCs
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Client returns a scraper client whose requests go to this server
+func (s *Server) Client(opts ...gplay.ClientOption) *gplay.Client {
+	return gplay.NewClient(append(opts, gplay.WithTransport(s.Transport()))...)
+}
+
+// Transport returns a transport that redirects Google Play and image requests
+// to this server, for use with WithTransport
+func (s *Server) Transport() http.RoundTripper {
+	target, _ := url.Parse(s.URL)
+	return &redirectTransport{target: target}
+}
+
+// originalHost carries the host a redirected request was meant for
+const originalHost = "X-Playtest-Host"
+
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Header.Set(originalHost, req.URL.Host)
+	out.URL.Scheme = t.target.Scheme
+	out.URL.Host = t.target.Host
+	out.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(out)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.Header.Get(originalHost), "googleusercontent.com") {
+		s.record("GET asset")
+		serveImage(w, r)
+		return
+	}
+
+	switch path := r.URL.Path; {
+	case strings.HasSuffix(path, "/batchexecute") && r.Method == http.MethodPost:
+		s.serveRPC(w, r)
+	case r.Method != http.MethodGet:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	case path == "/store/apps/details":
+		s.record("GET " + path)
+		s.serveDetails(w, r)
+	case path == "/store/apps/datasafety":
+		s.record("GET " + path)
+		s.serveDataSafety(w, r)
+	case path == "/store/search":
+		s.record("GET " + path)
+		s.serveSearch(w, r)
+	case path == "/store/apps/top", strings.HasPrefix(path, "/store/apps/category/"):
+		s.record("GET " + path)
+		s.serveChart(w, r)
+	case path == "/store/apps/dev", path == "/store/apps/developer":
+		s.record("GET " + path)
+		s.serveDeveloper(w, r, path == "/store/apps/dev")
+	case path == "/store/apps/collection/cluster":
+		s.record("GET " + path)
+		s.serveCluster(w, r)
+	default:
+		s.record("GET " + path)
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) record(req string) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+}
+
+// lookup returns copies of the apps with the given IDs, skipping unknown ones
+func (s *Server) lookup(ids []string) []gplay.App {
+	apps := make([]gplay.App, 0, len(ids))
+	for _, id := range ids {
+		if app, ok := s.apps[id]; ok {
+			apps = append(apps, *app)
+		}
+	}
+	return apps
+}
+
+func (s *Server) serveDetails(w http.ResponseWriter, r *http.Request) {
+	appID := r.URL.Query().Get("id")
+	s.mu.Lock()
+	app, ok := s.apps[appID]
+	var similar []string
+	if ok {
+		similar = s.similar[appID]
+	}
+	s.mu.Unlock()
+
+	if !ok || app.Availability == gplay.AvailabilityNotFound {
+		http.NotFound(w, r)
+		return
+	}
+	writePage(w, detailsPage(app, len(similar) > 0))
+}
+
+func (s *Server) serveDataSafety(w http.ResponseWriter, r *http.Request) {
+	appID := r.URL.Query().Get("id")
+	s.mu.Lock()
+	_, known := s.apps[appID]
+	ds := s.dataSafety[appID]
+	s.mu.Unlock()
+
+	if !known {
+		http.NotFound(w, r)
+		return
+	}
+	if ds == nil {
+		ds = &gplay.DataSafety{}
+	}
+	writePage(w, dataSafetyPage(ds))
+}
+
+func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	term, price := q.Get("q"), q.Get("price")
+	page, next := paginate(s.search(term, price), 0)
+	var token string
+	if next > 0 {
+		token = encodeToken(term, price, strconv.Itoa(next))
+	}
+
+	s.mu.Lock()
+	featured := s.lookup([]string{s.featured[strings.ToLower(term)]})
+	sections := append([]searchSection(nil), s.sections[strings.ToLower(term)]...)
+	for i := range sections {
+		sections[i].apps = s.lookup(sections[i].ids)
+	}
+	s.mu.Unlock()
+	writePage(w, searchPage(featured, page, token, sections))
+}
+
+// search returns added apps whose title or summary contains term.
+// price follows the search URL: "1" free only, "2" paid only.
+func (s *Server) search(term, price string) []gplay.App {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	term = strings.ToLower(term)
+	var results []gplay.App
+	for _, id := range s.order {
+		app := s.apps[id]
+		if !strings.Contains(strings.ToLower(app.Title), term) && !strings.Contains(strings.ToLower(app.Summary), term) {
+			continue
+		}
+		if (price == "1" && !app.Free) || (price == "2" && app.Free) {
+			continue
+		}
+		results = append(results, *app)
+	}
+	return results
+}
+
+func (s *Server) serveChart(w http.ResponseWriter, r *http.Request) {
+	category := gplay.CategoryApplication
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/store/apps/category/"); ok {
+		category = gplay.Category(rest)
+	}
+
+	s.mu.Lock()
+	sections := make([][]gplay.App, 3)
+	for i, collection := range []gplay.Collection{gplay.CollectionTopFree, gplay.CollectionTopPaid, gplay.CollectionGrossing} {
+		sections[i] = s.lookup(s.charts[chartKey{collection, category}])
+	}
+	s.mu.Unlock()
+
+	writePage(w, chartPage(sections))
+}
+
+func (s *Server) serveDeveloper(w http.ResponseWriter, r *http.Request, numeric bool) {
+	devID := r.URL.Query().Get("id")
+
+	s.mu.Lock()
+	var apps []gplay.App
+	for _, id := range s.order {
+		app := s.apps[id]
+		if app.DeveloperID == devID || (!numeric && app.Developer == devID) {
+			apps = append(apps, *app)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(apps) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	writePage(w, developerPage(apps, numeric))
+}
+
+func (s *Server) serveCluster(w http.ResponseWriter, r *http.Request) {
+	appID, ok := strings.CutPrefix(r.URL.Query().Get("gsr"), "similar:")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	apps := s.lookup(s.similar[appID])
+	s.mu.Unlock()
+
+	writePage(w, clusterPage(apps))
+}
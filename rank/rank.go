@@ -0,0 +1,380 @@
+// Package rank tracks where apps rank in Google Play search for a set of
+// keywords and countries.
+//
+// Each Check searches every keyword in every country, records the position of
+// each tracked app in a snapshot.Store, and reports how positions moved since
+// the previous check. The first check of an app only records a baseline.
+package rank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+	"github.com/kryuchenko/google-play-scraper/snapshot"
+)
+
+// Source is the part of *googleplayscraper.Client the tracker uses
+type Source interface {
+	Search(ctx context.Context, opts gplay.SearchOptions) ([]gplay.SearchResult, error)
+}
+
+// kindRanks stores one app's positions for every keyword and country of a check
+const kindRanks snapshot.Kind = "ranks"
+
+// Config configures a Tracker
+type Config struct {
+	Keywords  []string
+	Countries []string // Country codes (default "us")
+	Apps      []string // App IDs to track
+	Lang      string
+
+	Depth    int           // Results searched per keyword; apps below are absent (default 100)
+	Interval time.Duration // Time between checks in Run (default 24h)
+
+	// A check runs len(Keywords) * len(Countries) searches
+	Workers  int           // Searches run at once (default 1)
+	Throttle time.Duration // Minimum time between the start of two searches
+
+	Store   snapshot.Store // Persists the time series (required)
+	Handler func(Movement) // Called for each movement found by Run
+	OnError func(error)    // Called for failed searches and checks; concurrently when Workers > 1
+}
+
+// Rank is an app's position for a keyword in a country
+type Rank struct {
+	Keyword  string `json:"keyword"`
+	Country  string `json:"country"`
+	Position int    `json:"position"` // 1-based; 0 when absent from the first Depth results
+}
+
+// Movement is a change in an app's position between two checks
+type Movement struct {
+	AppID    string    `json:"appId"`
+	Keyword  string    `json:"keyword"`
+	Country  string    `json:"country"`
+	Previous int       `json:"previous"` // 0 when the app was absent
+	Current  int       `json:"current"`  // 0 when the app dropped out
+	Time     time.Time `json:"time"`
+}
+
+// Change returns how many places the app climbed; negative values are drops.
+// It is 0 when the app entered or dropped out of the results.
+func (m Movement) Change() int {
+	if m.Previous == 0 || m.Current == 0 {
+		return 0
+	}
+	return m.Previous - m.Current
+}
+
+// Entered reports whether the app appeared in the results
+func (m Movement) Entered() bool {
+	return m.Previous == 0 && m.Current > 0
+}
+
+// Dropped reports whether the app fell out of the results
+func (m Movement) Dropped() bool {
+	return m.Previous > 0 && m.Current == 0
+}
+
+// Point is an app's position for a keyword at the time of a check
+type Point struct {
+	Time     time.Time `json:"time"`
+	Position int       `json:"position"` // 0 when absent
+}
+
+// Tracker searches keywords and records app positions
+type Tracker struct {
+	src Source
+	cfg Config
+	now func() time.Time
+}
+
+// New creates a tracker for the given source and configuration
+func New(src Source, cfg Config) (*Tracker, error) {
+	if src == nil {
+		return nil, fmt.Errorf("source is required")
+	}
+	if cfg.Store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
+	if len(cfg.Keywords) == 0 {
+		return nil, fmt.Errorf("at least one keyword is required")
+	}
+	if len(cfg.Apps) == 0 {
+		return nil, fmt.Errorf("at least one app is required")
+	}
+	if len(cfg.Countries) == 0 {
+		cfg.Countries = []string{"us"}
+	}
+	if cfg.Lang == "" {
+		cfg.Lang = "en"
+	}
+	if cfg.Depth == 0 {
+		cfg.Depth = 100
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = 24 * time.Hour
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	return &Tracker{src: src, cfg: cfg, now: time.Now}, nil
+}
+
+// key identifies a keyword in a country
+type key struct {
+	keyword string
+	country string
+}
+
+// Check searches every keyword in every country, records each app's
+// positions and returns the movements since the previous check. Keywords
+// whose search failed are reported to OnError and left out of the check, so
+// a failure never looks like an app dropping out; the next check compares
+// them with the last check that searched them.
+func (t *Tracker) Check(ctx context.Context) ([]Movement, error) {
+	positions, searched, err := t.searchAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(searched) == 0 {
+		return nil, fmt.Errorf("every search failed")
+	}
+
+	now := t.now()
+	var movements []Movement
+	for _, appID := range t.cfg.Apps {
+		ranks := make([]Rank, len(searched))
+		for i, k := range searched {
+			ranks[i] = Rank{Keyword: k.keyword, Country: k.country, Position: positions[k][appID]}
+		}
+
+		prev, err := t.previous(ctx, appID, searched)
+		if err != nil {
+			return movements, err
+		}
+		rec, err := snapshot.NewRecord(appID, kindRanks, now, ranks)
+		if err != nil {
+			return movements, err
+		}
+		if err := t.cfg.Store.Append(ctx, rec); err != nil {
+			return movements, err
+		}
+
+		for _, r := range ranks {
+			before, ok := prev[key{r.Keyword, r.Country}]
+			if !ok || before == r.Position {
+				continue
+			}
+			movements = append(movements, Movement{
+				AppID:    appID,
+				Keyword:  r.Keyword,
+				Country:  r.Country,
+				Previous: before,
+				Current:  r.Position,
+				Time:     now,
+			})
+		}
+	}
+	return movements, nil
+}
+
+// searchAll runs every search on Workers goroutines, returning each app's
+// position per searched key and the keys whose search succeeded, in
+// configuration order
+func (t *Tracker) searchAll(ctx context.Context) (map[key]map[string]int, []key, error) {
+	var keys []key
+	for _, country := range t.cfg.Countries {
+		for _, keyword := range t.cfg.Keywords {
+			keys = append(keys, key{keyword, country})
+		}
+	}
+
+	var throttle <-chan time.Time
+	if t.cfg.Throttle > 0 {
+		ticker := time.NewTicker(t.cfg.Throttle)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	found := make([]map[string]int, len(keys))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < t.cfg.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				found[i] = t.search(ctx, keys[i])
+			}
+		}()
+	}
+	for i := range keys {
+		if i > 0 && throttle != nil {
+			select {
+			case <-ctx.Done():
+			case <-throttle:
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	positions := make(map[key]map[string]int)
+	var searched []key
+	for i, k := range keys {
+		if found[i] != nil {
+			positions[k] = found[i]
+			searched = append(searched, k)
+		}
+	}
+	return positions, searched, nil
+}
+
+// search returns the position of each app in the results for k, or nil if
+// the search failed
+func (t *Tracker) search(ctx context.Context, k key) map[string]int {
+	results, err := t.src.Search(ctx, gplay.SearchOptions{
+		Term:    k.keyword,
+		Lang:    t.cfg.Lang,
+		Country: k.country,
+		Num:     t.cfg.Depth,
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			t.reportError(fmt.Errorf("search %q in %s: %w", k.keyword, k.country, err))
+		}
+		return nil
+	}
+	positions := make(map[string]int)
+	for i, r := range results {
+		if _, ok := positions[r.AppID]; !ok {
+			positions[r.AppID] = i + 1
+		}
+	}
+	return positions
+}
+
+// previous returns the app's last recorded position for each key. Keys the
+// latest check didn't search fall back to the last check that did.
+func (t *Tracker) previous(ctx context.Context, appID string, keys []key) (map[key]int, error) {
+	rec, err := snapshot.Latest(ctx, t.cfg.Store, appID, kindRanks)
+	if err != nil || rec == nil {
+		return nil, err
+	}
+	prev := make(map[key]int)
+	if err := addRanks(prev, *rec); err != nil {
+		return nil, err
+	}
+	if hasKeys(prev, keys) {
+		return prev, nil
+	}
+
+	records, err := t.cfg.Store.List(ctx, appID, snapshot.Query{Kind: kindRanks})
+	if err != nil {
+		return nil, err
+	}
+	for i := len(records) - 1; i >= 0 && !hasKeys(prev, keys); i-- {
+		if err := addRanks(prev, records[i]); err != nil {
+			return nil, err
+		}
+	}
+	return prev, nil
+}
+
+// addRanks adds the positions of rec for keys prev doesn't have yet
+func addRanks(prev map[key]int, rec snapshot.Record) error {
+	ranks, err := decode(rec)
+	if err != nil {
+		return err
+	}
+	for _, r := range ranks {
+		k := key{r.Keyword, r.Country}
+		if _, ok := prev[k]; !ok {
+			prev[k] = r.Position
+		}
+	}
+	return nil
+}
+
+func hasKeys(prev map[key]int, keys []key) bool {
+	for _, k := range keys {
+		if _, ok := prev[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Run checks on every interval until ctx is cancelled, passing each movement
+// to the handler. The first check runs immediately.
+func (t *Tracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		movements, err := t.Check(ctx)
+		if err != nil && ctx.Err() == nil {
+			t.reportError(err)
+		}
+		if t.cfg.Handler != nil {
+			for _, m := range movements {
+				t.cfg.Handler(m)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) reportError(err error) {
+	if t.cfg.OnError != nil {
+		t.cfg.OnError(err)
+	}
+}
+
+// History returns an app's position for a keyword in a country at every
+// check in [from, to) that searched it. Zero times leave that end of the
+// range open.
+func History(ctx context.Context, s snapshot.Store, appID, keyword, country string, from, to time.Time) ([]Point, error) {
+	records, err := s.List(ctx, appID, snapshot.Query{Kind: kindRanks, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	var points []Point
+	for _, rec := range records {
+		ranks, err := decode(rec)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range ranks {
+			if r.Keyword == keyword && r.Country == country {
+				points = append(points, Point{Time: rec.Time, Position: r.Position})
+				break
+			}
+		}
+	}
+	return points, nil
+}
+
+func decode(rec snapshot.Record) ([]Rank, error) {
+	var ranks []Rank
+	if err := json.Unmarshal(rec.Data, &ranks); err != nil {
+		return nil, fmt.Errorf("decode %s snapshot: %w", kindRanks, err)
+	}
+	return ranks, nil
+}
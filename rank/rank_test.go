@@ -0,0 +1,288 @@
+package rank
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+	"github.com/kryuchenko/google-play-scraper/playtest"
+	"github.com/kryuchenko/google-play-scraper/snapshot"
+)
+
+// fakeSource returns the app IDs set for each country and keyword
+type fakeSource struct {
+	mu      sync.Mutex
+	results map[string][]string // "country/keyword" -> app IDs
+	fail    map[string]bool
+	opts    []gplay.SearchOptions
+}
+
+func (f *fakeSource) set(country, keyword string, appIDs ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.results == nil {
+		f.results = make(map[string][]string)
+	}
+	f.results[country+"/"+keyword] = appIDs
+}
+
+func (f *fakeSource) Search(ctx context.Context, opts gplay.SearchOptions) ([]gplay.SearchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.opts = append(f.opts, opts)
+	k := opts.Country + "/" + opts.Term
+	if f.fail[k] {
+		return nil, errors.New("search failed")
+	}
+	var results []gplay.SearchResult
+	for _, id := range f.results[k] {
+		results = append(results, gplay.SearchResult{AppID: id})
+	}
+	return results, nil
+}
+
+func newTestTracker(t *testing.T, src Source, store snapshot.Store, cfg Config) *Tracker {
+	t.Helper()
+	cfg.Store = store
+	tr, err := New(src, cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	day := 0
+	tr.now = func() time.Time {
+		day++
+		return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC)
+	}
+	return tr
+}
+
+func TestNewValidation(t *testing.T) {
+	store := snapshot.NewMemoryStore()
+	cfg := Config{Keywords: []string{"notes"}, Apps: []string{"com.example"}, Store: store}
+	if _, err := New(nil, cfg); err == nil {
+		t.Error("expected error for nil source")
+	}
+	for name, bad := range map[string]func(*Config){
+		"store":    func(c *Config) { c.Store = nil },
+		"keywords": func(c *Config) { c.Keywords = nil },
+		"apps":     func(c *Config) { c.Apps = nil },
+	} {
+		c := cfg
+		bad(&c)
+		if _, err := New(&fakeSource{}, c); err == nil {
+			t.Errorf("expected error without %s", name)
+		}
+	}
+}
+
+func TestCheckReportsMovements(t *testing.T) {
+	ctx := context.Background()
+	src := &fakeSource{}
+	src.set("us", "notes", "com.other", "com.example")
+	src.set("us", "todo", "com.example")
+	src.set("de", "notes", "com.other")
+	src.set("de", "todo")
+	tr := newTestTracker(t, src, snapshot.NewMemoryStore(), Config{
+		Keywords:  []string{"notes", "todo"},
+		Countries: []string{"us", "de"},
+		Apps:      []string{"com.example"},
+		Depth:     50,
+	})
+
+	if movements, err := tr.Check(ctx); err != nil || len(movements) != 0 {
+		t.Fatalf("baseline: got %+v, %v", movements, err)
+	}
+	if len(src.opts) != 4 || src.opts[0].Num != 50 || src.opts[0].Lang != "en" {
+		t.Errorf("searches: %+v", src.opts)
+	}
+
+	src.set("us", "notes", "com.example", "com.other") // 2 -> 1
+	src.set("us", "todo")                              // 1 -> absent
+	src.set("de", "notes", "com.other", "com.example") // absent -> 2
+	movements, err := tr.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(movements) != 3 {
+		t.Fatalf("expected 3 movements, got %+v", movements)
+	}
+
+	up, dropped, entered := movements[0], movements[1], movements[2]
+	if up.Keyword != "notes" || up.Country != "us" || up.Change() != 1 || up.Entered() || up.Dropped() {
+		t.Errorf("climb: %+v", up)
+	}
+	if dropped.Keyword != "todo" || !dropped.Dropped() || dropped.Previous != 1 || dropped.Change() != 0 {
+		t.Errorf("drop: %+v", dropped)
+	}
+	if entered.Country != "de" || !entered.Entered() || entered.Current != 2 {
+		t.Errorf("entry: %+v", entered)
+	}
+	if !up.Time.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("movement time: %v", up.Time)
+	}
+}
+
+func TestFailedSearchIsNotADrop(t *testing.T) {
+	ctx := context.Background()
+	src := &fakeSource{fail: map[string]bool{}}
+	src.set("us", "notes", "com.example")
+	src.set("us", "todo", "com.example")
+	var errs []error
+	tr := newTestTracker(t, src, snapshot.NewMemoryStore(), Config{
+		Keywords: []string{"notes", "todo"},
+		Apps:     []string{"com.example"},
+		OnError:  func(err error) { errs = append(errs, err) },
+	})
+
+	if _, err := tr.Check(ctx); err != nil {
+		t.Fatalf("baseline: %v", err)
+	}
+	src.fail["us/todo"] = true
+	if movements, err := tr.Check(ctx); err != nil || len(movements) != 0 {
+		t.Fatalf("failed search: got %+v, %v", movements, err)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected one reported error, got %v", errs)
+	}
+
+	// The next successful search compares with the last check that ran it
+	src.fail["us/todo"] = false
+	src.set("us", "todo", "com.other", "com.example")
+	movements, err := tr.Check(ctx)
+	if err != nil || len(movements) != 1 || movements[0].Keyword != "todo" || movements[0].Previous != 1 || movements[0].Current != 2 {
+		t.Fatalf("after recovery: got %+v, %v", movements, err)
+	}
+
+	src.fail["us/notes"] = true
+	src.fail["us/todo"] = true
+	if _, err := tr.Check(ctx); err == nil {
+		t.Error("expected error when every search fails")
+	}
+}
+
+func TestCheckWorkersAndThrottle(t *testing.T) {
+	ctx := context.Background()
+	src := &fakeSource{}
+	keywords := []string{"a", "b", "c", "d", "e", "f"}
+	for _, k := range keywords {
+		src.set("us", k, "com.example")
+	}
+	store := snapshot.NewMemoryStore()
+	tr := newTestTracker(t, src, store, Config{
+		Keywords: keywords,
+		Apps:     []string{"com.example"},
+		Workers:  3,
+		Throttle: 5 * time.Millisecond,
+	})
+
+	start := time.Now()
+	if _, err := tr.Check(ctx); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("6 throttled searches took %v", elapsed)
+	}
+	if len(src.opts) != len(keywords) {
+		t.Errorf("expected %d searches, got %d", len(keywords), len(src.opts))
+	}
+
+	rec, err := snapshot.Latest(ctx, store, "com.example", kindRanks)
+	if err != nil || rec == nil {
+		t.Fatalf("Latest: %v, %v", rec, err)
+	}
+	ranks, _ := decode(*rec)
+	for i, r := range ranks {
+		if r.Keyword != keywords[i] || r.Position != 1 {
+			t.Errorf("rank %d: %+v", i, r)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	store := snapshot.NewMemoryStore()
+	srv := playtest.NewServer()
+	defer srv.Close()
+	tr := newTestTracker(t, srv.Client(), store, Config{Keywords: []string{"notes"}, Apps: []string{"com.example"}})
+
+	// Search lists the apps whose title matches in the order they were added
+	srv.AddApp(gplay.App{AppID: "com.other", Title: "Other"})
+	for _, titles := range [][2]string{
+		{"Other", "Notes"},        // com.example first
+		{"Other notes", "Notes"},  // com.other moves ahead
+		{"Other notes", "Ledger"}, // com.example drops out
+	} {
+		srv.AddApp(gplay.App{AppID: "com.other", Title: titles[0]})
+		srv.AddApp(gplay.App{AppID: "com.example", Title: titles[1]})
+		if _, err := tr.Check(ctx); err != nil {
+			t.Fatalf("Check: %v", err)
+		}
+	}
+
+	points, err := History(ctx, store, "com.example", "notes", "us", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	want := []int{1, 2, 0}
+	if len(points) != len(want) {
+		t.Fatalf("got %+v", points)
+	}
+	for i, p := range points {
+		if p.Position != want[i] || p.Time.Day() != i+1 {
+			t.Errorf("point %d: %+v", i, p)
+		}
+	}
+
+	recent, err := History(ctx, store, "com.example", "notes", "us", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), time.Time{})
+	if err != nil || len(recent) != 2 {
+		t.Errorf("from day 2: %+v, %v", recent, err)
+	}
+	if other, _ := History(ctx, store, "com.example", "notes", "de", time.Time{}, time.Time{}); len(other) != 0 {
+		t.Errorf("unsearched country: %+v", other)
+	}
+}
+
+func TestRunCallsHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	src := &fakeSource{}
+	src.set("us", "notes", "com.example")
+
+	var mu sync.Mutex
+	var got []Movement
+	tr := newTestTracker(t, src, snapshot.NewMemoryStore(), Config{
+		Keywords: []string{"notes"},
+		Apps:     []string{"com.example"},
+		Interval: 10 * time.Millisecond,
+		Handler: func(m Movement) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, m)
+			cancel()
+		},
+	})
+	if _, err := tr.Check(ctx); err != nil {
+		t.Fatalf("baseline: %v", err)
+	}
+	src.set("us", "notes", "com.other", "com.example")
+
+	done := make(chan error)
+	go func() { done <- tr.Run(ctx) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0].Change() != -1 {
+		t.Errorf("handler got %+v", got)
+	}
+}
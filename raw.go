@@ -0,0 +1,56 @@
+package googleplayscraper
+
+// RawData is the decoded Google Play data a result was parsed from, for
+// reading fields the library doesn't map. Walk it with Path.
+type RawData struct {
+	Blocks map[string]interface{} `json:"blocks,omitempty"` // Page data blocks by key ("ds:5"), for page results
+	RPC    interface{}            `json:"rpc,omitempty"`    // Inner JSON of the batchexecute response, for RPC results
+	Node   interface{}            `json:"node"`             // The result's own entry: app block, card or review
+}
+
+// WithRawData keeps the decoded data on App, SearchResult, Review,
+// DataSafety, Permission and SuggestResult results in their Raw field. Results parsed from the same
+// response share its Blocks or RPC value.
+func WithRawData() ClientOption {
+	return func(c *Client) {
+		c.rawData = true
+	}
+}
+
+// Path returns the value at indices in decoded Google Play JSON, or nil when
+// the path doesn't exist. Objects with numeric keys are indexed by key.
+//
+//	title, _ := googleplayscraper.Path(app.Raw.Node, 0, 0).(string)
+func Path(data interface{}, indices ...int) interface{} {
+	return getPath(data, indices...)
+}
+
+// rawResults drops the raw data parsers attach unless WithRawData is set
+func (c *Client) rawResults(results []SearchResult) []SearchResult {
+	if !c.rawData {
+		for i := range results {
+			results[i].Raw = nil
+		}
+	}
+	return results
+}
+
+// rawReviews drops the raw data parsers attach unless WithRawData is set
+func (c *Client) rawReviews(reviews []Review) []Review {
+	if !c.rawData {
+		for i := range reviews {
+			reviews[i].Raw = nil
+		}
+	}
+	return reviews
+}
+
+// rawPermissions drops the raw data parsers attach unless WithRawData is set
+func (c *Client) rawPermissions(perms []Permission) []Permission {
+	if !c.rawData {
+		for i := range perms {
+			perms[i].Raw = nil
+		}
+	}
+	return perms
+}
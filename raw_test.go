@@ -0,0 +1,134 @@
+package googleplayscraper
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	data := []interface{}{"a", []interface{}{nil, map[string]interface{}{"138": []interface{}{"deep"}}}}
+
+	tests := []struct {
+		path []int
+		want interface{}
+	}{
+		{[]int{0}, "a"},
+		{[]int{1, 1, 138, 0}, "deep"},
+		{[]int{1, 0, 0}, nil},
+		{[]int{2}, nil},
+		{[]int{-1}, nil},
+		{[]int{0, 0}, nil},
+	}
+	for _, tt := range tests {
+		if got := Path(data, tt.path...); got != tt.want {
+			t.Errorf("Path(%v) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWithRawData(t *testing.T) {
+	page, err := os.ReadFile("testdata/details_full.html")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	ctx := context.Background()
+
+	app, err := NewClient(WithTransport(&fixtureTransport{body: page})).App(ctx, "com.anvil.ledger", AppOptions{})
+	if err != nil {
+		t.Fatalf("App: %v", err)
+	}
+	if app.Raw != nil {
+		t.Error("raw data kept without WithRawData")
+	}
+
+	app, err = NewClient(WithTransport(&fixtureTransport{body: page}), WithRawData()).App(ctx, "com.anvil.ledger", AppOptions{})
+	if err != nil {
+		t.Fatalf("App: %v", err)
+	}
+	if app.Raw == nil || app.Raw.Blocks["ds:5"] == nil {
+		t.Fatalf("raw blocks missing: %+v", app.Raw)
+	}
+	if title := Path(app.Raw.Node, 0, 0); title != app.Title {
+		t.Errorf("raw node title = %v, want %q", title, app.Title)
+	}
+}
+
+func TestWithRawDataRPC(t *testing.T) {
+	body, err := os.ReadFile("testdata/reviews_rpc.txt")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	c := NewClient(WithTransport(&fixtureTransport{body: body}), WithRawData())
+	result, err := c.Reviews(context.Background(), "com.anvil.ledger", ReviewOptions{})
+	if err != nil {
+		t.Fatalf("Reviews: %v", err)
+	}
+	if len(result.Reviews) != 1 {
+		t.Fatalf("got %d reviews", len(result.Reviews))
+	}
+	raw := result.Reviews[0].Raw
+	if raw == nil || raw.RPC == nil {
+		t.Fatalf("raw RPC data missing: %+v", raw)
+	}
+	if id := Path(raw.Node, 0); id != result.Reviews[0].ID {
+		t.Errorf("raw node id = %v", id)
+	}
+	if Path(raw.RPC, 0, 0) == nil {
+		t.Error("raw RPC data does not hold the reviews")
+	}
+}
+
+// rpcEnvelope wraps inner in a batchexecute response for rpcID
+func rpcEnvelope(t *testing.T, rpcID string, inner interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := json.Marshal([]interface{}{[]interface{}{"wrb.fr", rpcID, string(data), nil, nil, nil, "generic"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append([]byte(")]}'\n"), envelope...)
+}
+
+func TestWithRawDataPermissionsAndSuggest(t *testing.T) {
+	ctx := context.Background()
+	perms := rpcEnvelope(t, "xdSrCf", []interface{}{
+		[]interface{}{[]interface{}{"Location", nil, []interface{}{[]interface{}{nil, "precise location"}}}},
+	})
+	suggest := rpcEnvelope(t, "IJ4APc", []interface{}{[]interface{}{[]interface{}{[]interface{}{"maps"}}}})
+
+	got, err := fixtureClient(t, perms).Permissions(ctx, PermissionsOptions{AppID: "com.anvil.ledger"})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("Permissions: %v, %+v", err, got)
+	}
+	if got[0].Raw != nil {
+		t.Error("raw data kept without WithRawData")
+	}
+	got, err = fixtureClient(t, perms, WithRawData()).Permissions(ctx, PermissionsOptions{AppID: "com.anvil.ledger"})
+	if err != nil || len(got) != 1 {
+		t.Fatalf("Permissions: %v, %+v", err, got)
+	}
+	if raw := got[0].Raw; raw == nil || raw.RPC == nil || Path(raw.Node, 1) != "precise location" {
+		t.Errorf("permission raw data = %+v", raw)
+	}
+
+	result, err := fixtureClient(t, suggest).SuggestResults(ctx, SuggestOptions{Term: "ma"})
+	if err != nil {
+		t.Fatalf("SuggestResults: %v", err)
+	}
+	if result.Raw != nil {
+		t.Error("raw data kept without WithRawData")
+	}
+	result, err = fixtureClient(t, suggest, WithRawData()).SuggestResults(ctx, SuggestOptions{Term: "ma"})
+	if err != nil {
+		t.Fatalf("SuggestResults: %v", err)
+	}
+	if len(result.Suggestions) != 1 || result.Raw == nil || Path(result.Raw.RPC, 0, 0, 0, 0) != "maps" {
+		t.Errorf("suggest result = %+v", result)
+	}
+}
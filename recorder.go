@@ -0,0 +1,206 @@
+package googleplayscraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrNoRecording is returned in replay mode for requests missing from the cassette
+var ErrNoRecording = errors.New("no recorded response")
+
+// RecordMode selects whether a Recorder captures or serves responses
+type RecordMode int
+
+const (
+	ModeRecord RecordMode = iota // Send requests and save every response
+	ModeReplay                   // Serve saved responses; never touch the network
+)
+
+// Interaction is one recorded request/response pair
+type Interaction struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	RequestBody string `json:"requestBody,omitempty"`
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
+	BodyBase64  []byte `json:"bodyBase64,omitempty"` // Used instead of Body for non-UTF-8 responses
+}
+
+// Recorder is an http.RoundTripper that records responses to a cassette
+// directory or replays them. Install it with WithTransport.
+//
+// Each interaction is stored as a JSON file named after its endpoint and a
+// hash of method, URL and body, so re-recording overwrites in place.
+type Recorder struct {
+	dir  string
+	mode RecordMode
+
+	// Transport sends requests in record mode (default http.DefaultTransport)
+	Transport http.RoundTripper
+}
+
+// NewRecorder creates a recorder for the cassette directory dir.
+// Record mode creates the directory; replay mode requires it to exist.
+func NewRecorder(dir string, mode RecordMode) (*Recorder, error) {
+	switch mode {
+	case ModeRecord:
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create cassette dir: %w", err)
+		}
+	case ModeReplay:
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("open cassette dir: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown record mode %d", mode)
+	}
+	return &Recorder{dir: dir, mode: mode}, nil
+}
+
+// WithTransport sets the HTTP transport, e.g. a Recorder
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// RoundTrip records or replays a single request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+	}
+	path := r.path(req.Method, req.URL.String(), string(reqBody))
+
+	if r.mode == ModeReplay {
+		return r.replay(req, path)
+	}
+	return r.record(req, reqBody, path)
+}
+
+func (r *Recorder) path(method, rawURL, body string) string {
+	return filepath.Join(r.dir, fmt.Sprintf("%s-%s.json", endpointFor(rawURL, body), cacheKey(method, rawURL, body)[:16]))
+}
+
+func (r *Recorder) replay(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s", ErrNoRecording, req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+
+	var in Interaction
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("decode cassette %s: %w", filepath.Base(path), err)
+	}
+	body := []byte(in.Body)
+	if in.BodyBase64 != nil {
+		body = in.BodyBase64
+	}
+
+	header := make(http.Header)
+	if in.ContentType != "" {
+		header.Set("Content-Type", in.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) record(req *http.Request, reqBody []byte, path string) (*http.Response, error) {
+	// The caller's request is left as it is; the clone carries the buffered body
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(reqBody))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(reqBody)), nil
+		}
+	}
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.Request = req
+
+	in := Interaction{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		RequestBody: string(reqBody),
+		Status:      resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if utf8.Valid(body) {
+		in.Body = string(body)
+	} else {
+		in.BodyBase64 = body
+	}
+
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode cassette: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("write cassette: %w", err)
+	}
+	return resp, nil
+}
+
+// Interactions returns every recorded interaction in the cassette, for inspection
+func (r *Recorder) Interactions() ([]Interaction, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read cassette dir: %w", err)
+	}
+
+	var interactions []Interaction
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(r.dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read cassette: %w", err)
+		}
+		var in Interaction
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("decode cassette %s: %w", e.Name(), err)
+		}
+		interactions = append(interactions, in)
+	}
+	return interactions, nil
+}
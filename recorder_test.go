@@ -0,0 +1,185 @@
+package googleplayscraper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fixtureTransport serves a fixed body for every request, standing in for Google Play
+type fixtureTransport struct {
+	body     []byte
+	requests int
+}
+
+func (f *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.requests++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:       io.NopCloser(bytes.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(r.Method + " " + r.URL.Path + " " + string(body)))
+	}))
+
+	dir := t.TempDir()
+	rec, err := NewRecorder(dir, ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	c := NewClient(WithTransport(rec))
+	ctx := context.Background()
+
+	got, err := c.get(ctx, server.URL+"/store/apps/details?id=com.example")
+	if err != nil || string(got) != "GET /store/apps/details " {
+		t.Fatalf("record get: %q, %v", got, err)
+	}
+	got, err = c.post(ctx, server.URL+"/_/PlayStoreUi/data/batchexecute?rpcids=IJ4APc", "application/x-www-form-urlencoded", "f.req=x")
+	if err != nil || string(got) != "POST /_/PlayStoreUi/data/batchexecute f.req=x" {
+		t.Fatalf("record post: %q, %v", got, err)
+	}
+	server.Close()
+
+	interactions, err := rec.Interactions()
+	if err != nil || len(interactions) != 2 {
+		t.Fatalf("Interactions: got %d, %v", len(interactions), err)
+	}
+
+	replay, err := NewRecorder(dir, ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	c = NewClient(WithTransport(replay))
+
+	got, err = c.get(ctx, server.URL+"/store/apps/details?id=com.example")
+	if err != nil || string(got) != "GET /store/apps/details " {
+		t.Errorf("replay get: %q, %v", got, err)
+	}
+	got, err = c.post(ctx, server.URL+"/_/PlayStoreUi/data/batchexecute?rpcids=IJ4APc", "application/x-www-form-urlencoded", "f.req=x")
+	if err != nil || string(got) != "POST /_/PlayStoreUi/data/batchexecute f.req=x" {
+		t.Errorf("replay post: %q, %v", got, err)
+	}
+
+	_, err = c.post(ctx, server.URL+"/_/PlayStoreUi/data/batchexecute?rpcids=IJ4APc", "application/x-www-form-urlencoded", "f.req=y")
+	if !errors.Is(err, ErrNoRecording) {
+		t.Errorf("unmatched request: got %v, want ErrNoRecording", err)
+	}
+}
+
+func TestRecorderReplaysStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	dir := t.TempDir()
+	rec, _ := NewRecorder(dir, ModeRecord)
+	NewClient(WithTransport(rec)).get(context.Background(), server.URL+"/store/apps/details?id=com.gone")
+	server.Close()
+
+	replay, _ := NewRecorder(dir, ModeReplay)
+	_, err := NewClient(WithTransport(replay)).get(context.Background(), server.URL+"/store/apps/details?id=com.gone")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected recorded 404, got %v", err)
+	}
+}
+
+func TestRecorderBinaryBody(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00, 0xfe}
+	dir := t.TempDir()
+	rec, _ := NewRecorder(dir, ModeRecord)
+	rec.Transport = &fixtureTransport{body: png}
+	url := "https://play-lh.googleusercontent.com/icon=s0"
+	if _, err := NewClient(WithTransport(rec)).get(context.Background(), url); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	replay, _ := NewRecorder(dir, ModeReplay)
+	got, err := NewClient(WithTransport(replay)).get(context.Background(), url)
+	if err != nil || !bytes.Equal(got, png) {
+		t.Errorf("binary body: got %v, %v", got, err)
+	}
+}
+
+func TestRecorderAppOffline(t *testing.T) {
+	page, err := os.ReadFile("testdata/details_full.html")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+
+	dir := t.TempDir()
+	rec, _ := NewRecorder(dir, ModeRecord)
+	upstream := &fixtureTransport{body: page}
+	rec.Transport = upstream
+	recorded, err := NewClient(WithTransport(rec)).App(context.Background(), "com.anvil.ledger", AppOptions{})
+	if err != nil {
+		t.Fatalf("record App: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || !strings.HasPrefix(entries[0].Name(), "details-") {
+		t.Errorf("expected one details cassette file, got %v", entries)
+	}
+
+	replay, _ := NewRecorder(dir, ModeReplay)
+	app, err := NewClient(WithTransport(replay)).App(context.Background(), "com.anvil.ledger", AppOptions{})
+	if err != nil {
+		t.Fatalf("replay App: %v", err)
+	}
+	if app.Title != recorded.Title || app.Version != recorded.Version {
+		t.Errorf("replayed app differs: %q %q vs %q %q", app.Title, app.Version, recorded.Title, recorded.Version)
+	}
+	if upstream.requests != 1 {
+		t.Errorf("replay should not reach the transport, got %d requests", upstream.requests)
+	}
+
+	if _, err := NewClient(WithTransport(replay)).App(context.Background(), "com.other", AppOptions{}); !errors.Is(err, ErrNoRecording) {
+		t.Errorf("unrecorded app: got %v, want ErrNoRecording", err)
+	}
+}
+
+func TestRecorderLeavesRequestUnchanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	rec, err := NewRecorder(t.TempDir(), ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder: %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/_/PlayStoreUi/data/batchexecute", strings.NewReader("f.req=1"))
+	body := req.Body
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip: %v", err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != "f.req=1" {
+		t.Errorf("forwarded body: %q", got)
+	}
+	if req.Body != body {
+		t.Error("RoundTrip replaced the caller's request body")
+	}
+	if resp.Request != req {
+		t.Error("response should point at the caller's request")
+	}
+}
+
+func TestNewRecorderReplayMissingDir(t *testing.T) {
+	if _, err := NewRecorder(t.TempDir()+"/missing", ModeReplay); err == nil {
+		t.Error("expected error for missing cassette dir")
+	}
+}
@@ -2,12 +2,16 @@ package googleplayscraper
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/kryuchenko/google-play-scraper/batchexecute"
 )
 
 // Client handles HTTP requests to Google Play
@@ -17,6 +21,12 @@ type Client struct {
 	throttle     time.Duration
 	lastRequest  time.Time
 	throttleLock sync.Mutex
+	cache        Cache
+	cacheTTL     time.Duration
+	endpointTTL  map[Endpoint]time.Duration
+	parseReport  func(ParseReport)
+	rawData      bool
+	strict       bool
 }
 
 // ClientOption configures the client
@@ -75,8 +85,21 @@ func (c *Client) waitThrottle() {
 	c.lastRequest = time.Now()
 }
 
-// get performs a GET request
+// get performs a GET request, served from the cache when one is configured
 func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	return c.cached(ctx, http.MethodGet, url, "", func() ([]byte, error) {
+		return c.doGet(ctx, url)
+	})
+}
+
+// post performs a POST request, served from the cache when one is configured
+func (c *Client) post(ctx context.Context, url string, contentType string, body string) ([]byte, error) {
+	return c.cached(ctx, http.MethodPost, url, body, func() ([]byte, error) {
+		return c.doPost(ctx, url, contentType, body)
+	})
+}
+
+func (c *Client) doGet(ctx context.Context, url string) ([]byte, error) {
 	c.waitThrottle()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -95,7 +118,7 @@ func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode, URL: url}
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -106,8 +129,7 @@ func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
 	return body, nil
 }
 
-// post performs a POST request
-func (c *Client) post(ctx context.Context, url string, contentType string, body string) ([]byte, error) {
+func (c *Client) doPost(ctx context.Context, url string, contentType string, body string) ([]byte, error) {
 	c.waitThrottle()
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
@@ -126,7 +148,7 @@ func (c *Client) post(ctx context.Context, url string, contentType string, body
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode, URL: url}
 	}
 
 	respBody, err := io.ReadAll(resp.Body)
@@ -137,6 +159,40 @@ func (c *Client) post(ctx context.Context, url string, contentType string, body
 	return respBody, nil
 }
 
+// rpc sends calls in one batchexecute request and returns the raw response
+func (c *Client) rpc(ctx context.Context, lang, country string, calls ...batchexecute.Call) ([]byte, error) {
+	body, err := batchexecute.Encode(calls...)
+	if err != nil {
+		return nil, err
+	}
+	reqURL := fmt.Sprintf("%s/_/PlayStoreUi/data/batchexecute?rpcids=%s&hl=%s&gl=%s",
+		BaseURL, batchexecute.RPCIDs(calls...), lang, country)
+	return c.post(ctx, reqURL, "application/x-www-form-urlencoded;charset=UTF-8", body)
+}
+
+// rpcData decodes the payload of a single-call batchexecute response
+func rpcData(body []byte, rpcID string) ([]interface{}, error) {
+	resp, err := batchexecute.Decode(body)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := resp.Get(rpcID, "")
+	if err != nil {
+		return nil, err
+	}
+	var data []interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("parse %s data: %w", rpcID, err)
+	}
+	return data, nil
+}
+
+// noRPCData reports whether err means the response carried no payload for the call
+func noRPCData(err error) bool {
+	var rpcErr *batchexecute.Error
+	return errors.Is(err, batchexecute.ErrNoResult) || errors.As(err, &rpcErr)
+}
+
 // buildURL constructs a Google Play URL
 func buildURL(path string, params map[string]string) string {
 	url := BaseURL + path
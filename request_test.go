@@ -2,6 +2,7 @@ package googleplayscraper
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -66,7 +67,15 @@ func TestClientGetError(t *testing.T) {
 	c := NewClient()
 	_, err := c.get(context.Background(), server.URL)
 	if err == nil {
-		t.Error("expected error for 404 status")
+		t.Fatal("expected error for 404 status")
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected *StatusError, got %T", err)
+	}
+	if statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("StatusCode: got %d, want %d", statusErr.StatusCode, http.StatusNotFound)
 	}
 }
 
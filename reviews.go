@@ -2,10 +2,10 @@ package googleplayscraper
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/url"
 	"time"
+
+	"github.com/kryuchenko/google-play-scraper/batchexecute"
 )
 
 // ReviewsComprehensive fetches reviews by querying each rating separately to maximize unique results.
@@ -95,87 +95,57 @@ func (c *Client) Reviews(ctx context.Context, appID string, opts ReviewOptions)
 		opts.Count = 150
 	}
 
-	body := buildReviewsBody(appID, opts)
-	reqURL := fmt.Sprintf("%s/_/PlayStoreUi/data/batchexecute?hl=%s&gl=%s", BaseURL, opts.Lang, opts.Country)
-
-	respBody, err := c.post(ctx, reqURL, "application/x-www-form-urlencoded", body)
+	respBody, err := c.rpc(ctx, opts.Lang, opts.Country, reviewsCall(appID, opts))
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	return parseReviewsResponse(respBody, appID)
+	rep := c.newReport(EndpointReviews, "oCPfdb")
+	result, err := parseReviewsResponse(respBody, appID, rep)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.sendReport(rep, len(result.Reviews)); err != nil {
+		return nil, err
+	}
+	result.Reviews = c.rawReviews(result.Reviews)
+	return result, nil
 }
 
-func buildReviewsBody(appID string, opts ReviewOptions) string {
+// reviewsCall builds [null,[2,sort,[count(,null,token)],null,[null,score]],[appID,7]]
+func reviewsCall(appID string, opts ReviewOptions) batchexecute.Call {
 	count := opts.Count
 	if count > 150 {
 		count = 150 // Google Play limit per request
 	}
 
-	// Build filter score part (null if not filtering, otherwise the score)
-	scorePart := "null"
+	// Filter score is null unless filtering by rating
+	var score any
 	if opts.FilterScore >= 1 && opts.FilterScore <= 5 {
-		scorePart = fmt.Sprintf("%d", opts.FilterScore)
+		score = opts.FilterScore
 	}
 
-	var payload string
-	if opts.NextToken == "" {
-		// Initial request
-		payload = fmt.Sprintf(
-			`[[["oCPfdb","[null,[2,%d,[%d],null,[null,%s]],[\"%s\",7]]",null,"generic"]]]`,
-			opts.Sort, count, scorePart, appID,
-		)
-	} else {
-		// Paginated request
-		payload = fmt.Sprintf(
-			`[[["oCPfdb","[null,[2,%d,[%d,null,\"%s\"],null,[null,%s]],[\"%s\",7]]",null,"generic"]]]`,
-			opts.Sort, count, opts.NextToken, scorePart, appID,
-		)
+	page := []any{count}
+	if opts.NextToken != "" {
+		page = append(page, nil, opts.NextToken)
 	}
 
-	return "f.req=" + url.QueryEscape(payload)
-}
-
-func parseReviewsResponse(body []byte, appID string) (*ReviewsResult, error) {
-	// Response starts with )]}'  which we need to skip
-	start := 0
-	for i := range body {
-		if body[i] == '\n' {
-			start = i + 1
-			break
-		}
-	}
-
-	if start >= len(body) {
-		return nil, fmt.Errorf("invalid response format")
-	}
-
-	// Parse outer JSON array
-	var outer [][]interface{}
-	if err := json.Unmarshal(body[start:], &outer); err != nil {
-		return nil, fmt.Errorf("parse outer json: %w", err)
-	}
-
-	if len(outer) == 0 || len(outer[0]) < 3 {
-		return nil, fmt.Errorf("unexpected response structure")
-	}
-
-	// The data is in outer[0][2] as a JSON string
-	dataStr, ok := outer[0][2].(string)
-	if !ok {
-		return nil, fmt.Errorf("data is not a string")
+	return batchexecute.Call{
+		RPCID: "oCPfdb",
+		Args:  []any{nil, []any{2, opts.Sort, page, nil, []any{nil, score}}, []any{appID, 7}},
 	}
+}
 
-	// Parse the inner JSON
-	var data []interface{}
-	if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-		return nil, fmt.Errorf("parse inner json: %w", err)
+func parseReviewsResponse(body []byte, appID string, rep *ParseReport) (*ReviewsResult, error) {
+	data, err := rpcData(body, "oCPfdb")
+	if err != nil {
+		return nil, err
 	}
 
-	return extractReviews(data, appID)
+	return extractReviews(data, appID, rep)
 }
 
-func extractReviews(data []interface{}, appID string) (*ReviewsResult, error) {
+func extractReviews(data []interface{}, appID string, rep *ParseReport) (*ReviewsResult, error) {
 	result := &ReviewsResult{
 		Reviews: []Review{},
 	}
@@ -187,17 +157,21 @@ func extractReviews(data []interface{}, appID string) (*ReviewsResult, error) {
 	// Extract reviews array from data[0]
 	reviewsData, ok := data[0].([]interface{})
 	if !ok {
+		rep.mismatch("oCPfdb[0]", "reviews not found")
 		return result, nil
 	}
 
-	for _, item := range reviewsData {
-		review, err := parseReview(item, appID)
+	for i, item := range reviewsData {
+		rep.at("oCPfdb[0][%d]", i)
+		review, err := parseReview(item, appID, rep)
 		if err != nil {
+			rep.mismatch(rep.location(), "skipped: %v", err)
 			continue // Skip malformed reviews
 		}
 		if review.ID == "" {
 			continue // Skip empty reviews
 		}
+		review.Raw.RPC = data
 		result.Reviews = append(result.Reviews, review)
 	}
 
@@ -213,90 +187,15 @@ func extractReviews(data []interface{}, appID string) (*ReviewsResult, error) {
 	return result, nil
 }
 
-func parseReview(item interface{}, appID string) (Review, error) {
-	arr, ok := item.([]interface{})
-	if !ok {
+func parseReview(item interface{}, appID string, rep *ParseReport) (Review, error) {
+	if _, ok := item.([]interface{}); !ok {
 		return Review{}, fmt.Errorf("review is not an array")
 	}
 
-	review := Review{}
-
-	// ID: [0]
-	if len(arr) > 0 {
-		if id, ok := arr[0].(string); ok {
-			review.ID = id
-			review.URL = fmt.Sprintf("%s/store/apps/details?id=%s&reviewId=%s", BaseURL, appID, id)
-		}
-	}
-
-	// UserName: [1][0]
-	if len(arr) > 1 {
-		if userData, ok := arr[1].([]interface{}); ok && len(userData) > 0 {
-			if name, ok := userData[0].(string); ok {
-				review.UserName = name
-			}
-			// UserImage: [1][1][3][2]
-			if len(userData) > 1 {
-				if imgData, ok := userData[1].([]interface{}); ok && len(imgData) > 3 {
-					if imgInner, ok := imgData[3].([]interface{}); ok && len(imgInner) > 2 {
-						if img, ok := imgInner[2].(string); ok {
-							review.UserImage = img
-						}
-					}
-				}
-			}
-		}
-	}
-
-	// Score: [2]
-	if len(arr) > 2 {
-		if score, ok := arr[2].(float64); ok {
-			review.Score = int(score)
-		}
-	}
-
-	// Text: [4]
-	if len(arr) > 4 {
-		if text, ok := arr[4].(string); ok {
-			review.Text = text
-		}
-	}
-
-	// Date: [5]
-	if len(arr) > 5 {
-		if dateArr, ok := arr[5].([]interface{}); ok {
-			review.Date = parseTimestamp(dateArr)
-		}
-	}
-
-	// ThumbsUp: [6]
-	if len(arr) > 6 {
-		if thumbs, ok := arr[6].(float64); ok {
-			review.ThumbsUp = int(thumbs)
-		}
-	}
-
-	// ReplyText: [7][1], ReplyDate: [7][2]
-	if len(arr) > 7 {
-		if replyData, ok := arr[7].([]interface{}); ok {
-			if len(replyData) > 1 {
-				if replyText, ok := replyData[1].(string); ok {
-					review.ReplyText = replyText
-				}
-			}
-			if len(replyData) > 2 {
-				if replyDateArr, ok := replyData[2].([]interface{}); ok {
-					review.ReplyDate = parseTimestamp(replyDateArr)
-				}
-			}
-		}
-	}
-
-	// Version: [10]
-	if len(arr) > 10 {
-		if version, ok := arr[10].(string); ok {
-			review.Version = version
-		}
+	review := Review{Raw: &RawData{Node: item}}
+	applyFields(&review, item, reviewFields, rep)
+	if review.ID != "" {
+		review.URL = fmt.Sprintf("%s/store/apps/details?id=%s&reviewId=%s", BaseURL, appID, review.ID)
 	}
 
 	return review, nil
@@ -2,37 +2,42 @@ package googleplayscraper
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 )
 
-func TestBuildReviewsBody(t *testing.T) {
+func TestReviewsCall(t *testing.T) {
 	tests := []struct {
-		name  string
-		appID string
-		opts  ReviewOptions
+		name string
+		opts ReviewOptions
+		want string
 	}{
 		{
-			name:  "initial request",
-			appID: "com.example.app",
-			opts:  ReviewOptions{Sort: SortNewest, Count: 100},
+			name: "initial request",
+			opts: ReviewOptions{Sort: SortNewest, Count: 100},
+			want: `[null,[2,2,[100],null,[null,null]],["com.example.app",7]]`,
 		},
 		{
-			name:  "paginated request",
-			appID: "com.example.app",
-			opts:  ReviewOptions{Sort: SortNewest, Count: 100, NextToken: "abc123"},
+			name: "paginated request",
+			opts: ReviewOptions{Sort: SortRating, Count: 500, NextToken: "abc123", FilterScore: 4},
+			want: `[null,[2,3,[150,null,"abc123"],null,[null,4]],["com.example.app",7]]`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			body := buildReviewsBody(tt.appID, tt.opts)
-			if body == "" {
-				t.Error("body is empty")
+			call := reviewsCall("com.example.app", tt.opts)
+			if call.RPCID != "oCPfdb" {
+				t.Errorf("RPCID = %q", call.RPCID)
+			}
+			got, err := json.Marshal(call.Args)
+			if err != nil {
+				t.Fatalf("marshal args: %v", err)
 			}
-			if len(body) < 10 {
-				t.Error("body is too short")
+			if string(got) != tt.want {
+				t.Errorf("args = %s, want %s", got, tt.want)
 			}
 		})
 	}
@@ -99,7 +104,7 @@ func TestParseReview(t *testing.T) {
 		nil,                                // [7] Reply
 	}
 
-	review, err := parseReview(reviewData, "com.example.app")
+	review, err := parseReview(reviewData, "com.example.app", nil)
 	if err != nil {
 		t.Fatalf("parseReview failed: %v", err)
 	}
@@ -139,7 +144,7 @@ func TestReviewsWithMockServer(t *testing.T) {
 
 func TestParseReviewsResponse(t *testing.T) {
 	// Case 1: Empty response (should error or return empty)
-	res, err := parseReviewsResponse([]byte{}, "com.example")
+	res, err := parseReviewsResponse([]byte{}, "com.example", nil)
 	// Expect error because it tries to find JSON array or specific prefix
 	if err == nil {
 		t.Error("expected error for empty response")
@@ -149,7 +154,7 @@ func TestParseReviewsResponse(t *testing.T) {
 	// Case 2: Invalid JSON prefix handling
 	// parseReviewsResponse looks for `)]}'` prefix or tries to parse directly.
 	// If garbage, json unmarshal fails.
-	_, err = parseReviewsResponse([]byte("invalid json"), "com.example")
+	_, err = parseReviewsResponse([]byte("invalid json"), "com.example", nil)
 	if err == nil {
 		t.Error("expected error for invalid JSON")
 	}
@@ -158,7 +163,7 @@ func TestParseReviewsResponse(t *testing.T) {
 	// reviews.go expects: [0][2] as string with inner JSON
 	validOuter := `)]}'
 [["wrb.fr","bad-structure",null,"generic"]]`
-	_, err = parseReviewsResponse([]byte(validOuter), "com.example")
+	_, err = parseReviewsResponse([]byte(validOuter), "com.example", nil)
 	// string assertion for [0][2] should fail or inner uncharshal fails
 	if err == nil {
 		// Actually if outer[0][2] is null, assertion to string fails, returns error.
@@ -167,8 +172,8 @@ func TestParseReviewsResponse(t *testing.T) {
 
 	// Case 4: Valid internal structure but empty data
 	validOuter2 := `)]}'
-[["wrb.fr","rpcId","[[null,[],null]]","generic"]]`
-	res2, err2 := parseReviewsResponse([]byte(validOuter2), "com.example")
+[["wrb.fr","oCPfdb","[[null,[],null]]","generic"]]`
+	res2, err2 := parseReviewsResponse([]byte(validOuter2), "com.example", nil)
 	if err2 != nil {
 		t.Errorf("unexpected error: %v", err2)
 	}
@@ -2,10 +2,12 @@ package googleplayscraper
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"strings"
+
+	"github.com/kryuchenko/google-play-scraper/batchexecute"
 )
 
 // SearchOptions configures the search request
@@ -14,34 +16,70 @@ type SearchOptions struct {
 	Lang       string
 	Country    string
 	Num        int
+	All        bool   // Follow pagination until results run out, ignoring Num
 	Price      string // "free", "paid", "all"
 	FullDetail bool
 }
 
 // SearchResult represents a search result item
 type SearchResult struct {
-	AppID       string  `json:"appId"`
-	Title       string  `json:"title"`
-	URL         string  `json:"url"`
-	Icon        string  `json:"icon"`
-	Developer   string  `json:"developer"`
-	DeveloperID string  `json:"developerId"`
-	Currency    string  `json:"currency"`
-	Price       float64 `json:"price"`
-	Free        bool    `json:"free"`
-	Summary     string  `json:"summary"`
-	ScoreText   string  `json:"scoreText"`
-	Score       float64 `json:"score"`
+	AppID       string   `json:"appId"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	Icon        string   `json:"icon"`
+	Developer   string   `json:"developer"`
+	DeveloperID string   `json:"developerId"`
+	Currency    string   `json:"currency"`
+	Price       float64  `json:"price"`
+	Free        bool     `json:"free"`
+	Summary     string   `json:"summary"`
+	Installs    string   `json:"installs"`
+	Genre       string   `json:"genre"`
+	ScoreText   string   `json:"scoreText"`
+	Score       float64  `json:"score"`
+	Featured    bool     `json:"featured,omitempty"` // The top result card, in the featured section
+	Raw         *RawData `json:"raw,omitempty"`      // Set with WithRawData
+}
+
+// SectionKind identifies a search page section that isn't part of the results
+type SectionKind string
+
+const (
+	SectionFeatured SectionKind = "featured" // The top result card shown above the list
+	SectionAds      SectionKind = "ads"      // Sponsored apps
+	SectionRelated  SectionKind = "related"  // Clusters such as "You might also like"
+)
+
+// SearchSection is a group of apps shown alongside the search results
+type SearchSection struct {
+	Kind    SectionKind    `json:"kind"`
+	Title   string         `json:"title"`
+	Results []SearchResult `json:"results"`
+}
+
+// SearchPage is a search split into organic results and the other sections
+// of the first page
+type SearchPage struct {
+	Results  []SearchResult  `json:"results"`
+	Sections []SearchSection `json:"sections,omitempty"`
 }
 
-// Search searches for apps on Google Play
+// Search searches for apps on Google Play. Only organic results are returned,
+// so positions match the result list; use SearchPage to get the featured,
+// ad and related sections.
 func (c *Client) Search(ctx context.Context, opts SearchOptions) ([]SearchResult, error) {
-	if opts.Term == "" {
-		return nil, fmt.Errorf("search term is required")
+	page, err := c.SearchPage(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
+	return page.Results, nil
+}
 
-	if opts.Num > 250 {
-		return nil, fmt.Errorf("number of results can't exceed 250")
+// SearchPage searches for apps on Google Play, keeping the featured, ad and
+// related sections of the first page apart from the results
+func (c *Client) SearchPage(ctx context.Context, opts SearchOptions) (*SearchPage, error) {
+	if opts.Term == "" {
+		return nil, fmt.Errorf("search term is required")
 	}
 
 	if opts.Lang == "" {
@@ -63,32 +101,72 @@ func (c *Client) Search(ctx context.Context, opts SearchOptions) ([]SearchResult
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	results, token, err := parseSearchPage(body, opts.Num)
+	rep := c.newReport(EndpointSearch, searchURL)
+	page, token, err := parseSearchPage(body, rep)
 	if err != nil {
 		return nil, err
 	}
+	records := len(page.Results)
+	for _, s := range page.Sections {
+		records += len(s.Results)
+	}
+	if err := c.sendReport(rep, records); err != nil {
+		return nil, err
+	}
 
-	// Fetch more results if needed
-	for len(results) < opts.Num && token != "" {
+	// Later pages can overlap earlier ones
+	seen := make(map[string]bool)
+	results := addUnseen(nil, page.Results, seen)
+
+	// Fetch more results if needed, stopping if a token comes back twice or
+	// a page adds nothing new
+	tokens := make(map[string]bool)
+	for (opts.All || len(results) < opts.Num) && token != "" && !tokens[token] {
+		tokens[token] = true
 		moreResults, nextToken, err := c.fetchMoreSearchResults(ctx, token, opts)
 		if err != nil {
+			if c.strict {
+				return nil, err
+			}
+			break
+		}
+		n := len(results)
+		results = addUnseen(results, moreResults, seen)
+		if len(results) == n {
 			break
 		}
-		results = append(results, moreResults...)
 		token = nextToken
 	}
 
 	// Trim to requested number
-	if len(results) > opts.Num {
+	if !opts.All && len(results) > opts.Num {
 		results = results[:opts.Num]
 	}
 
 	// Fetch full details if requested
 	if opts.FullDetail {
-		return c.enrichSearchResults(ctx, results, opts.Lang, opts.Country)
+		if results, err = c.enrichSearchResults(ctx, results, opts.Lang, opts.Country); err != nil {
+			return nil, err
+		}
+	} else {
+		results = c.rawResults(results)
+	}
+	page.Results = results
+	for i := range page.Sections {
+		page.Sections[i].Results = c.rawResults(page.Sections[i].Results)
 	}
+	return page, nil
+}
 
-	return results, nil
+// addUnseen appends the results whose app isn't in seen yet
+func addUnseen(results, more []SearchResult, seen map[string]bool) []SearchResult {
+	for _, r := range more {
+		if !seen[r.AppID] {
+			seen[r.AppID] = true
+			results = append(results, r)
+		}
+	}
+	return results
 }
 
 func getPriceValue(price string) int {
@@ -102,88 +180,136 @@ func getPriceValue(price string) int {
 	}
 }
 
-func parseSearchPage(body []byte, num int) ([]SearchResult, string, error) {
-	html := string(body)
+func parseSearchPage(body []byte, rep *ParseReport) (*SearchPage, string, error) {
+	dataBlocks := parseDataBlocks(body)
 
-	// Find data blocks
-	dataBlocks := make(map[string]interface{})
-	matches := scriptDataRegex.FindAllStringSubmatch(html, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 {
-			continue
-		}
-		key := match[1]
-		dataStr := strings.TrimSpace(match[2])
-
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			continue
-		}
-		dataBlocks[key] = data
-	}
+	return extractSearchResults(dataBlocks, rep)
+}
 
-	return extractSearchResults(dataBlocks)
+// Sections of a search page sit in [0][1]. The results are in the first
+// section at [22][0], [21][0] or [0][0], followed at [0][3][0] by the
+// pagination token. The sections after it keep their content under one
+// index: the featured card at [23][0], and ad and related clusters as
+// [cards, [title]] at [20] and [21].
+var searchResultPaths = [][]int{
+	{22, 0}, // developer pages
+	{21, 0},
+	{0, 0}, // search pages
 }
 
-func extractSearchResults(data map[string]interface{}) ([]SearchResult, string, error) {
-	var results []SearchResult
+func extractSearchResults(data map[string]interface{}, rep *ParseReport) (*SearchPage, string, error) {
+	page := &SearchPage{}
 	var token string
 
 	// Try ds:4 first (search), ds:3 (developer), then ds:1
 	var appsData interface{}
-	if ds4, ok := data["ds:4"]; ok {
-		appsData = ds4
-	} else if ds3, ok := data["ds:3"]; ok {
-		appsData = ds3
-	} else if ds1, ok := data["ds:1"]; ok {
-		appsData = ds1
-	} else {
-		return results, "", nil
+	key := ""
+	for _, k := range []string{"ds:4", "ds:3", "ds:1"} {
+		if ds, ok := data[k]; ok {
+			appsData, key = ds, k
+			break
+		}
 	}
-
-	// Navigate to apps: [0][1][0][0][0] or variations
-	// Developer pages: [0][1][0][22][0]
-	// Search pages: [0][1][0][0][0]
-	paths := [][]int{
-		{0, 1, 0, 22, 0}, // developer pages
-		{0, 1, 0, 21, 0},
-		{0, 1, 0, 0, 0},  // search pages
+	if key == "" {
+		rep.mismatch("ds:4", "data block not found")
+		return page, "", nil
+	}
+	if key != "ds:4" {
+		rep.mismatch("ds:4", "data block not found, using %s", key)
 	}
 
+	sections, _ := getPath(appsData, 0, 1).([]interface{})
 	var apps []interface{}
-	for _, path := range paths {
-		if section := getPath(appsData, path...); section != nil {
-			if arr, ok := section.([]interface{}); ok && len(arr) > 0 {
-				// Verify this looks like apps data
+	var appsPath string
+	if len(sections) > 0 {
+		for _, path := range searchResultPaths {
+			if arr, ok := getPath(sections[0], path...).([]interface{}); ok && len(arr) > 0 {
 				apps = arr
+				appsPath = key + "[0][1][0]" + pathString(path)
 				break
 			}
 		}
+		if t := getPath(sections[0], 0, 3, 0); t != nil {
+			token = toString(t)
+		}
+	}
+
+	for i := 1; i < len(sections); i++ {
+		if s, ok := extractSearchSection(sections[i], fmt.Sprintf("%s[0][1][%d]", key, i), data, rep); ok {
+			page.Sections = append(page.Sections, s)
+		}
 	}
 
 	if apps == nil {
-		// Try to find apps by scanning structure
-		apps = findAppsInData(appsData)
+		// Try to find apps by scanning the results section, or the whole
+		// block when there are no sections
+		rep.mismatch(key+"[0][1][0][0][0]", "results not found, scanning the block")
+		if len(sections) > 0 {
+			apps = findAppsInData(sections[0])
+			appsPath = key + "[0][1][0][?]"
+		} else {
+			apps = findAppsInData(appsData)
+			appsPath = key + "[?]"
+		}
 	}
 
-	for _, app := range apps {
-		result := parseSearchResultNew(app)
+	for i, app := range apps {
+		rep.at("%s[%d]", appsPath, i)
+		result := parseSearchResultNew(app, rep)
 		if result.AppID != "" {
-			results = append(results, result)
+			result.Raw.Blocks = data
+			page.Results = append(page.Results, result)
 		}
 	}
 
-	// Get token for pagination
-	if tokenData := getPath(appsData, 0, 1, 0, 0, 3, 0); tokenData != nil {
-		token = toString(tokenData)
+	return page, token, nil
+}
+
+// extractSearchSection reads a featured, ad or related section. Untitled
+// clusters at [21] aren't related sections.
+func extractSearchSection(section interface{}, base string, data map[string]interface{}, rep *ParseReport) (SearchSection, bool) {
+	if card := getPath(section, 23, 0); card != nil {
+		rep.at("%s[23][0]", base)
+		result := parseSearchResultNew(card, rep)
+		if result.AppID == "" {
+			return SearchSection{}, false
+		}
+		result.Featured = true
+		result.Raw.Blocks = data
+		return SearchSection{Kind: SectionFeatured, Results: []SearchResult{result}}, true
+	}
+
+	kind, index := SectionAds, 20
+	if getPath(section, 20) == nil {
+		if _, ok := getPath(section, 21, 1, 0).(string); !ok {
+			return SearchSection{}, false
+		}
+		kind, index = SectionRelated, 21
 	}
 
-	return results, token, nil
+	s := SearchSection{Kind: kind, Title: toString(getPath(section, index, 1, 0))}
+	cards, _ := getPath(section, index, 0).([]interface{})
+	for j, card := range cards {
+		rep.at("%s[%d][0][%d]", base, index, j)
+		if result := parseSearchResultNew(card, rep); result.AppID != "" {
+			result.Raw.Blocks = data
+			s.Results = append(s.Results, result)
+		}
+	}
+	return s, true
 }
 
-// findAppsInData recursively searches for apps array in data
+// findAppsInData recursively searches for apps array in data. Arrays of
+// plain cards are preferred: a list of plain cards also looks like one wrapped
+// developer card, so wrapped cards are only tried when there are none.
 func findAppsInData(data interface{}) []interface{} {
+	if apps := scanForCards(data, cardPairPaths); apps != nil {
+		return apps
+	}
+	return scanForCards(data, wrappedCardPairPaths)
+}
+
+func scanForCards(data interface{}, paths [][]int) []interface{} {
 	arr, ok := data.([]interface{})
 	if !ok {
 		return nil
@@ -192,8 +318,8 @@ func findAppsInData(data interface{}) []interface{} {
 	// Check if this looks like an apps array (has appId-like structures)
 	for _, item := range arr {
 		if itemArr, ok := item.([]interface{}); ok {
-			// Look for com.* pattern in nested arrays indicating appId
-			if hasAppIdPattern(itemArr) {
+			// Look for an [appID, 7] pair indicating an app card
+			if hasAppIDPairAt(itemArr, paths) {
 				return arr
 			}
 		}
@@ -201,7 +327,7 @@ func findAppsInData(data interface{}) []interface{} {
 
 	// Recurse into nested arrays
 	for _, item := range arr {
-		if result := findAppsInData(item); result != nil {
+		if result := scanForCards(item, paths); result != nil {
 			return result
 		}
 	}
@@ -209,242 +335,116 @@ func findAppsInData(data interface{}) []interface{} {
 	return nil
 }
 
+// cardPairPaths are where a card holds its [appID, 7] pair: first in search
+// and chart cards, and at [12] in search RPC results
+var cardPairPaths = [][]int{{0}, {12}}
+
+// wrappedCardPairPaths are the pair's positions in developer cards wrapped in
+// one or two extra arrays
+var wrappedCardPairPaths = [][]int{{0, 0}, {0, 0, 0}}
+
 func hasAppIdPattern(arr []interface{}) bool {
-	// Check common positions for appId
-	// Search: [0][0] = "com.xxx"
-	// Developer wrapped: [0][0][0][0] = "com.xxx" (because each app is [[app_data]])
-	paths := [][]int{
-		{0, 0},
-		{0, 0, 0},
-		{0, 0, 0, 0}, // developer pages have extra wrapping
-		{12, 0},
-	}
+	return hasAppIDPairAt(arr, cardPairPaths) || hasAppIDPairAt(arr, wrappedCardPairPaths)
+}
+
+func hasAppIDPairAt(arr []interface{}, paths [][]int) bool {
 	for _, path := range paths {
-		val := getPath(arr, path...)
-		if val == nil {
-			continue
-		}
-		if s, ok := val.(string); ok {
-			if len(s) > 3 && (hasPackagePrefix(s)) {
-				return true
-			}
+		if isAppIDPair(getPath(arr, path...)) {
+			return true
 		}
 	}
 	return false
 }
 
-func hasPackagePrefix(s string) bool {
-	prefixes := []string{"com.", "org.", "io.", "me.", "net.", "app.", "dev."}
-	for _, p := range prefixes {
-		if len(s) > len(p) && s[:len(p)] == p {
-			return true
+// isAppIDPair reports whether v is the [appID, 7] pair Google Play uses to
+// reference an app; 7 is the document type of apps
+func isAppIDPair(v interface{}) bool {
+	pair, ok := v.([]interface{})
+	if !ok || len(pair) < 2 {
+		return false
+	}
+	id, _ := pair[0].(string)
+	n, ok := pair[1].(float64)
+	return ok && n == 7 && ValidAppID(id)
+}
+
+// ValidAppID reports whether id is a well-formed Android package name: two or
+// more dot-separated segments, each a letter followed by letters, digits or
+// underscores
+func ValidAppID(id string) bool {
+	segments := strings.Split(id, ".")
+	if len(segments) < 2 {
+		return false
+	}
+	for _, seg := range segments {
+		if seg == "" {
+			return false
+		}
+		for i, r := range seg {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			case i > 0 && (r >= '0' && r <= '9' || r == '_'):
+			default:
+				return false
+			}
 		}
 	}
-	return false
+	return true
 }
 
 // parseSearchResultNew handles the new data format
-func parseSearchResultNew(item interface{}) SearchResult {
+func parseSearchResultNew(item interface{}, rep *ParseReport) SearchResult {
 	arr, ok := item.([]interface{})
 	if !ok {
 		return SearchResult{}
 	}
 
 	// Each item might be wrapped: [[actual_app_data]]
-	// Unwrap if needed
 	if len(arr) == 1 {
 		if inner, ok := arr[0].([]interface{}); ok {
 			arr = inner
 		}
 	}
 
-	result := SearchResult{}
-
-	// AppID: Try multiple paths
-	// Format 1: [0][0] is array like ["com.xxx", 7]
-	// Format 2: [0][0][0] for developer pages (after unwrap)
-	appIDPaths := [][]int{
-		{0, 0, 0, 0}, // developer page format with extra wrap
-		{0, 0, 0},    // developer page format
-		{0, 0},       // search page format
-	}
-	for _, path := range appIDPaths {
-		if v := getPath(arr, path...); v != nil {
-			s := toString(v)
-			if hasPackagePrefix(s) {
-				result.AppID = s
-				break
-			}
-		}
-	}
-
-	// Title: [3]
-	if v := getPath(arr, 3); v != nil {
-		result.Title = toString(v)
-	}
-
-	// Icon: Try multiple paths
-	iconPaths := [][]int{
-		{1, 3, 2},
-		{0, 1, 3, 2},
-	}
-	for _, path := range iconPaths {
-		if v := getPath(arr, path...); v != nil {
-			if s := toString(v); s != "" {
-				result.Icon = s
-				break
-			}
-		}
-	}
-
-	// Developer: [14]
-	if v := getPath(arr, 14); v != nil {
-		result.Developer = toString(v)
-	}
-
-	// Score: [4][1]
-	if v := getPath(arr, 4, 1); v != nil {
-		result.Score = toFloat64(v)
-	}
-
-	// ScoreText: [4][0]
-	if v := getPath(arr, 4, 0); v != nil {
-		result.ScoreText = toString(v)
-	}
-
-	// Free by default
-	result.Free = true
-
-	// URL
-	if result.AppID != "" {
-		result.URL = fmt.Sprintf("%s/store/apps/details?id=%s", BaseURL, result.AppID)
-	}
-
-	return result
+	return parseCard(arr, searchCardFields, rep)
 }
 
-func parseSearchResult(item interface{}) SearchResult {
-	arr, ok := item.([]interface{})
-	if !ok {
+func parseSearchResult(item interface{}, rep *ParseReport) SearchResult {
+	if _, ok := item.([]interface{}); !ok {
 		return SearchResult{}
 	}
-
-	result := SearchResult{}
-
-	// Title: [2]
-	if v := getPath(arr, 2); v != nil {
-		result.Title = toString(v)
-	}
-
-	// AppID: [12][0]
-	if v := getPath(arr, 12, 0); v != nil {
-		result.AppID = toString(v)
-	}
-
-	// URL: [9][4][2]
-	if v := getPath(arr, 9, 4, 2); v != nil {
-		path := toString(v)
-		if path != "" {
-			result.URL = BaseURL + path
-		}
-	}
-
-	// Icon: [1][1][0][3][2]
-	if v := getPath(arr, 1, 1, 0, 3, 2); v != nil {
-		result.Icon = toString(v)
-	}
-
-	// Developer: [4][0][0][0]
-	if v := getPath(arr, 4, 0, 0, 0); v != nil {
-		result.Developer = toString(v)
-	}
-
-	// DeveloperID: [4][0][0][1][4][2]
-	if v := getPath(arr, 4, 0, 0, 1, 4, 2); v != nil {
-		link := toString(v)
-		if strings.Contains(link, "?id=") {
-			parts := strings.Split(link, "?id=")
-			if len(parts) > 1 {
-				result.DeveloperID = parts[1]
-			}
-		}
-	}
-
-	// Currency: [7][0][3][2][1][0][1]
-	if v := getPath(arr, 7, 0, 3, 2, 1, 0, 1); v != nil {
-		result.Currency = toString(v)
-	}
-
-	// Price: [7][0][3][2][1][0][0]
-	if v := getPath(arr, 7, 0, 3, 2, 1, 0, 0); v != nil {
-		price := toFloat64(v)
-		result.Price = price / 1000000
-		result.Free = price == 0
-	} else {
-		result.Free = true
-	}
-
-	// Summary: [4][1][1][1][1]
-	if v := getPath(arr, 4, 1, 1, 1, 1); v != nil {
-		result.Summary = toString(v)
-	}
-
-	// ScoreText: [6][0][2][1][0]
-	if v := getPath(arr, 6, 0, 2, 1, 0); v != nil {
-		result.ScoreText = toString(v)
-	}
-
-	// Score: [6][0][2][1][1]
-	if v := getPath(arr, 6, 0, 2, 1, 1); v != nil {
-		result.Score = toFloat64(v)
-	}
-
-	return result
+	return parseCard(item, searchRPCFields, rep)
 }
 
 func (c *Client) fetchMoreSearchResults(ctx context.Context, token string, opts SearchOptions) ([]SearchResult, string, error) {
-	// Use batchexecute for pagination
-	payload := fmt.Sprintf(`[[["qnKhOb","[[null,[[10,[10,50]],true,null,[96,27,4,8,57,30,110,79,11,16,49,1,3,9,12,104,55,56,51,10,34,77]],[null,\"%s\"]]",null,"generic"]]]`, token)
-
-	reqURL := fmt.Sprintf("%s/_/PlayStoreUi/data/batchexecute?hl=%s&gl=%s", BaseURL, opts.Lang, opts.Country)
-	body, err := c.post(ctx, reqURL, "application/x-www-form-urlencoded", "f.req="+url.QueryEscape(payload))
+	args := []any{[]any{
+		nil,
+		[]any{[]any{10, []any{10, 50}}, true, nil, []any{96, 27, 4, 8, 57, 30, 110, 79, 11, 16, 49, 1, 3, 9, 12, 104, 55, 56, 51, 10, 34, 77}},
+		[]any{nil, token},
+	}}
+	body, err := c.rpc(ctx, opts.Lang, opts.Country, batchexecute.Call{RPCID: "qnKhOb", Args: args})
 	if err != nil {
 		return nil, "", err
 	}
 
-	return parseSearchBatchResponse(body)
-}
-
-func parseSearchBatchResponse(body []byte) ([]SearchResult, string, error) {
-	// Skip the )]}'  prefix
-	start := 0
-	for i := range body {
-		if body[i] == '\n' {
-			start = i + 1
-			break
-		}
-	}
-
-	if start >= len(body) {
-		return nil, "", fmt.Errorf("invalid response")
-	}
-
-	var outer [][]interface{}
-	if err := json.Unmarshal(body[start:], &outer); err != nil {
+	rep := c.newReport(EndpointSearch, "qnKhOb")
+	results, nextToken, err := parseSearchBatchResponse(body, rep)
+	if err != nil {
 		return nil, "", err
 	}
-
-	if len(outer) == 0 || len(outer[0]) < 3 {
-		return nil, "", nil
+	if err := c.sendReport(rep, len(results)); err != nil {
+		return nil, "", err
 	}
+	return results, nextToken, nil
+}
 
-	dataStr, ok := outer[0][2].(string)
-	if !ok {
+func parseSearchBatchResponse(body []byte, rep *ParseReport) ([]SearchResult, string, error) {
+	data, err := rpcData(body, "qnKhOb")
+	if noRPCData(err) {
+		rep.mismatch("qnKhOb", "%v", err)
 		return nil, "", nil
 	}
-
-	var data []interface{}
-	if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
+	if err != nil {
 		return nil, "", err
 	}
 
@@ -452,14 +452,16 @@ func parseSearchBatchResponse(body []byte) ([]SearchResult, string, error) {
 	var nextToken string
 
 	// Apps in data[0][0][0]
-	if apps := getPath(data, 0, 0, 0); apps != nil {
-		if appsArr, ok := apps.([]interface{}); ok {
-			for _, app := range appsArr {
-				result := parseSearchResult(app)
-				if result.AppID != "" {
-					results = append(results, result)
-				}
-			}
+	appsArr, ok := getPath(data, 0, 0, 0).([]interface{})
+	if !ok {
+		rep.mismatch("qnKhOb[0][0][0]", "results not found")
+	}
+	for i, app := range appsArr {
+		rep.at("qnKhOb[0][0][0][%d]", i)
+		result := parseSearchResult(app, rep)
+		if result.AppID != "" {
+			result.Raw.RPC = data
+			results = append(results, result)
 		}
 	}
 
@@ -479,25 +481,45 @@ func (c *Client) enrichSearchResults(ctx context.Context, results []SearchResult
 			Country: country,
 		})
 		if err != nil {
+			if c.strict {
+				return nil, errors.Join(&ParseError{
+					Endpoint: EndpointDetails,
+					Source:   detailsURL(r.AppID),
+					Path:     fmt.Sprintf("results[%d]", i),
+					Msg:      "enrichment skipped",
+				}, err)
+			}
 			// Keep original result if enrichment fails
 			enriched[i] = r
+			if !c.rawData {
+				enriched[i].Raw = nil
+			}
 			continue
 		}
-		// Convert App to SearchResult with full details
-		enriched[i] = SearchResult{
-			AppID:       app.AppID,
-			Title:       app.Title,
-			URL:         app.URL,
-			Icon:        app.Icon,
-			Developer:   app.Developer,
-			DeveloperID: app.DeveloperID,
-			Currency:    app.Currency,
-			Price:       app.Price,
-			Free:        app.Free,
-			Summary:     app.Summary,
-			ScoreText:   app.ScoreText,
-			Score:       app.Score,
+		// Overlay the details on the result, which keeps fields such as
+		// Featured that only the search page knows
+		e := r
+		e.AppID = app.AppID
+		e.Title = app.Title
+		e.URL = app.URL
+		e.Icon = app.Icon
+		e.Developer = app.Developer
+		e.DeveloperID = app.DeveloperID
+		e.Currency = app.Currency
+		e.Price = app.Price
+		e.Free = app.Free
+		e.Summary = app.Summary
+		e.ScoreText = app.ScoreText
+		e.Score = app.Score
+		e.Raw = app.Raw
+		// Keep card values the details page lacks
+		if app.Installs != "" {
+			e.Installs = app.Installs
+		}
+		if app.Genre != "" {
+			e.Genre = app.Genre
 		}
+		enriched[i] = e
 	}
 	return enriched, nil
 }
@@ -2,6 +2,8 @@ package googleplayscraper
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -15,11 +17,6 @@ func TestSearchValidation(t *testing.T) {
 	if err == nil {
 		t.Error("expected error for empty term")
 	}
-
-	_, err = c.Search(context.Background(), SearchOptions{Term: "test", Num: 300})
-	if err == nil {
-		t.Error("expected error for num > 250")
-	}
 }
 
 func TestGetPriceValue(t *testing.T) {
@@ -63,7 +60,7 @@ func TestParseSearchResult(t *testing.T) {
 		[]interface{}{"com.test.app"}, // [12] AppID
 	}
 
-	result := parseSearchResult(data)
+	result := parseSearchResult(data, nil)
 
 	if result.Title != "Test App" {
 		t.Errorf("Title: got %q, want %q", result.Title, "Test App")
@@ -73,6 +70,116 @@ func TestParseSearchResult(t *testing.T) {
 	}
 }
 
+func TestParseSearchPageFields(t *testing.T) {
+	page, _, err := parseSearchPage(readFixture(t, "search.html"), nil)
+	if err != nil || len(page.Results) != 2 {
+		t.Fatalf("got %+v, %v", page, err)
+	}
+	results := page.Results
+
+	paid := results[0]
+	if paid.AppID != "com.anvil.ledger" || paid.Free || paid.Price != 4.99 || paid.Currency != "USD" {
+		t.Errorf("paid price: %+v", paid)
+	}
+	if paid.Summary != "Budgets that balance themselves" || paid.DeveloperID != "8123456789012345678" {
+		t.Errorf("paid summary and developer: %+v", paid)
+	}
+	if paid.Installs != "500,000+" || paid.Genre != "Finance" {
+		t.Errorf("paid installs and genre: %+v", paid)
+	}
+
+	free := results[1]
+	if free.AppID != "com.anvil.tally" || !free.Free || free.Price != 0 || free.Currency != "" {
+		t.Errorf("free price: %+v", free)
+	}
+	if free.Installs != "500+" || free.Genre != "Tools" || free.Summary != "Count anything" {
+		t.Errorf("free fields: %+v", free)
+	}
+}
+
+func TestParseSearchPageSections(t *testing.T) {
+	page, _, err := parseSearchPage(readFixture(t, "search_sections.html"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, r := range page.Results {
+		ids = append(ids, r.AppID)
+	}
+	if strings.Join(ids, " ") != "com.anvil.ledger com.anvil.tally" {
+		t.Errorf("results: %v", ids)
+	}
+
+	if len(page.Sections) != 3 {
+		t.Fatalf("got %d sections, want 3", len(page.Sections))
+	}
+	featured, ads, related := page.Sections[0], page.Sections[1], page.Sections[2]
+	if featured.Kind != SectionFeatured || len(featured.Results) != 1 || !featured.Results[0].Featured || featured.Results[0].AppID != "com.anvil.ledger" {
+		t.Errorf("featured: %+v", featured)
+	}
+	if ads.Kind != SectionAds || ads.Title != "Sponsored" || len(ads.Results) != 1 || ads.Results[0].AppID != "com.acme.budget" {
+		t.Errorf("ads: %+v", ads)
+	}
+	if related.Kind != SectionRelated || related.Title != "You might also like" || len(related.Results) != 2 {
+		t.Errorf("related: %+v", related)
+	}
+}
+
+func TestSearchSkipsSections(t *testing.T) {
+	c := fixtureClient(t, readFixture(t, "search_sections.html"), WithStrict())
+	results, err := c.Search(context.Background(), SearchOptions{Term: "ledger"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].AppID != "com.anvil.ledger" || results[0].Featured || results[1].AppID != "com.anvil.tally" {
+		t.Errorf("results: %+v", results)
+	}
+}
+
+func TestSearchResultsSectionWithClusterMarkers(t *testing.T) {
+	// The first section holds the results even if it also carries the
+	// indexes other sections use
+	cards := getPath(fixtureBlocks(t, "search.html")["ds:4"], 0, 1, 0, 0, 0)
+	section := []interface{}{[]interface{}{cards}}
+	for len(section) < 22 {
+		section = append(section, nil)
+	}
+	section[20] = []interface{}{}
+	section[21] = []interface{}{nil, []interface{}{"Title"}}
+	page, _, err := extractSearchResults(map[string]interface{}{"ds:4": []interface{}{[]interface{}{nil, []interface{}{section}}}}, nil)
+	if err != nil || len(page.Results) != 2 || len(page.Sections) != 0 {
+		t.Errorf("got %+v, %v", page, err)
+	}
+}
+
+func TestSearchStopsWhenPagesAddNothing(t *testing.T) {
+	cards := getPath(fixtureBlocks(t, "search.html")["ds:4"], 0, 1, 0, 0, 0)
+	first, _ := json.Marshal([]interface{}{[]interface{}{nil, []interface{}{[]interface{}{[]interface{}{cards, nil, nil, []interface{}{"t0"}}}}}})
+	apps := getPath(fixtureRPC(t, "search_rpc.txt", "qnKhOb"), 0, 0, 0)
+
+	var rpcs int
+	c := NewClient(WithTransport(&MockTransport{RoundTripFunc: func(req *http.Request) (*http.Response, error) {
+		body := fmt.Sprintf(`<script>AF_initDataCallback({key: 'ds:4', hash: '1', data:%s, sideChannel: {}});</script>`, first)
+		if req.Method == http.MethodPost {
+			// Every page repeats the same app under a fresh token
+			rpcs++
+			inner, _ := json.Marshal([]interface{}{[]interface{}{[]interface{}{apps, nil, nil, nil, nil, nil, nil, []interface{}{nil, fmt.Sprintf("t%d", rpcs)}}}})
+			envelope, _ := json.Marshal([]interface{}{[]interface{}{"wrb.fr", "qnKhOb", string(inner), nil, nil, nil, "generic"}})
+			body = ")]}'\n\n" + string(envelope)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header), Request: req}, nil
+	}}))
+
+	results, err := c.Search(context.Background(), SearchOptions{Term: "ledger", All: true})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("got %+v, %v", results, err)
+	}
+	if rpcs != 1 {
+		t.Errorf("expected to stop after one page without new results, made %d", rpcs)
+	}
+}
+
 // TestSearchIntegration is a real integration test
 func TestSearchIntegration(t *testing.T) {
 	if testing.Short() {
@@ -291,13 +398,13 @@ func TestSearchFullDetail(t *testing.T) {
 // TestParseSearchBatchResponse tests the batch response parser
 func TestParseSearchBatchResponse(t *testing.T) {
 	// Empty response
-	_, _, err := parseSearchBatchResponse([]byte{})
+	_, _, err := parseSearchBatchResponse([]byte{}, nil)
 	if err == nil {
 		t.Error("expected error for empty response")
 	}
 
 	// Invalid JSON
-	_, _, err = parseSearchBatchResponse([]byte("\n{invalid"))
+	_, _, err = parseSearchBatchResponse([]byte("\n{invalid"), nil)
 	if err == nil {
 		t.Error("expected error for invalid JSON")
 	}
@@ -305,7 +412,7 @@ func TestParseSearchBatchResponse(t *testing.T) {
 	// Valid but empty response (standard empty batch response format)
 	results, token, err := parseSearchBatchResponse([]byte(`
 [[["wrb.fr","[[null,[]],null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null]","null",null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null]]]
-`))
+`), nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
@@ -378,11 +485,13 @@ func TestHasAppIdPattern(t *testing.T) {
 		input []interface{}
 		want  bool
 	}{
-		{[]interface{}{[]interface{}{"com.example"}}, true},
-		{[]interface{}{[]interface{}{"net.example"}}, true},
-		{[]interface{}{[]interface{}{"io.example"}}, true},
-		{[]interface{}{[]interface{}{"not.package"}}, false},
-		{[]interface{}{[]interface{}{123}}, false},
+		{[]interface{}{[]interface{}{"com.example", float64(7)}}, true},
+		{[]interface{}{[]interface{}{"ru.yandex.taxi", float64(7)}}, true},
+		{[]interface{}{[]interface{}{[]interface{}{"jp.naver.line.android", float64(7)}}}, true},
+		{[]interface{}{[]interface{}{"com.example"}}, false},
+		{[]interface{}{[]interface{}{"play.google.com/store", float64(7)}}, false},
+		{[]interface{}{[]interface{}{"com.example", float64(3)}}, false},
+		{[]interface{}{[]interface{}{123, float64(7)}}, false},
 		{[]interface{}{}, false},
 	}
 
@@ -393,6 +502,54 @@ func TestHasAppIdPattern(t *testing.T) {
 	}
 }
 
+func TestValidAppID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"com.spotify.music", true},
+		{"ru.yandex.taxi", true},
+		{"jp.naver.line.android", true},
+		{"tv.twitch.android.app", true},
+		{"com.King.candy_crush2", true},
+		{"a.b", true},
+		{"example", false},
+		{"", false},
+		{"com..example", false},
+		{".com.example", false},
+		{"com.example.", false},
+		{"com.1example", false},
+		{"com._example", false},
+		{"com.exa-mple", false},
+		{"com.exämple", false},
+		{"play.google.com/store", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidAppID(tt.id); got != tt.want {
+			t.Errorf("ValidAppID(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestFindAppsInDataAnyPrefix(t *testing.T) {
+	cards := []interface{}{
+		[]interface{}{[]interface{}{"tv.twitch.android.app", float64(7)}, nil, nil, "Twitch"},
+		[]interface{}{[]interface{}{"jp.naver.line.android", float64(7)}, nil, nil, "LINE"},
+	}
+	data := []interface{}{nil, []interface{}{"play.google.com", []interface{}{cards}}}
+
+	apps := findAppsInData(data)
+	if len(apps) != 2 {
+		t.Fatalf("found %d apps, want 2", len(apps))
+	}
+	for i, want := range []string{"tv.twitch.android.app", "jp.naver.line.android"} {
+		if r := parseSearchResultNew(apps[i], nil); r.AppID != want {
+			t.Errorf("result %d AppID = %q, want %q", i, r.AppID, want)
+		}
+	}
+}
+
 // MockTransport allows mocking HTTP responses
 type MockTransport struct {
 	RoundTripFunc func(req *http.Request) (*http.Response, error)
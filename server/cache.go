@@ -0,0 +1,47 @@
+package server
+
+import "sync"
+
+// response is an encoded reply shared between coalesced and cached requests
+type response struct {
+	status int
+	body   []byte
+	err    string // Error returned along with the served data
+}
+
+// group coalesces concurrent calls with the same key into one
+type group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done chan struct{}
+	resp response
+}
+
+// do runs fn once per key at a time; callers arriving while it runs share its
+// result. shared reports whether the caller received another call's result.
+func (g *group) do(key string, fn func() response) (resp response, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.resp, true
+	}
+	c := &call{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.resp = fn()
+	return c.resp, false
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// metrics counts requests per endpoint and cache behaviour
+type metrics struct {
+	mu        sync.Mutex
+	requests  map[string]int64 // By endpoint
+	errors    map[string]int64 // Responses with status >= 400, by endpoint
+	upstream  int64            // Calls made to Google Play
+	hits      int64
+	misses    int64
+	coalesced int64
+}
+
+func newMetrics() *metrics {
+	return &metrics{requests: make(map[string]int64), errors: make(map[string]int64)}
+}
+
+func (m *metrics) add(f func(m *metrics)) {
+	m.mu.Lock()
+	f(m)
+	m.mu.Unlock()
+}
+
+// writeTo writes the counters in the Prometheus text format. The cache size
+// gauge is only written for caches that report their size.
+func (m *metrics) writeTo(w io.Writer, cache any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter := func(name, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	}
+	byEndpoint := func(name string, values map[string]int64) {
+		endpoints := make([]string, 0, len(values))
+		for e := range values {
+			endpoints = append(endpoints, e)
+		}
+		sort.Strings(endpoints)
+		for _, e := range endpoints {
+			fmt.Fprintf(w, "%s{endpoint=%q} %d\n", name, e, values[e])
+		}
+	}
+
+	counter("gplay_requests_total", "HTTP requests by endpoint.")
+	byEndpoint("gplay_requests_total", m.requests)
+	counter("gplay_errors_total", "Error responses by endpoint.")
+	byEndpoint("gplay_errors_total", m.errors)
+	counter("gplay_upstream_requests_total", "Scraper calls made to Google Play.")
+	fmt.Fprintf(w, "gplay_upstream_requests_total %d\n", m.upstream)
+	counter("gplay_cache_hits_total", "Responses served from the cache.")
+	fmt.Fprintf(w, "gplay_cache_hits_total %d\n", m.hits)
+	counter("gplay_cache_misses_total", "Requests not found in the cache.")
+	fmt.Fprintf(w, "gplay_cache_misses_total %d\n", m.misses)
+	counter("gplay_coalesced_total", "Requests that shared an in-flight identical request.")
+	fmt.Fprintf(w, "gplay_coalesced_total %d\n", m.coalesced)
+	if sized, ok := cache.(interface{ Len() int }); ok {
+		fmt.Fprintf(w, "# HELP gplay_cache_entries Responses currently cached.\n# TYPE gplay_cache_entries gauge\n")
+		fmt.Fprintf(w, "gplay_cache_entries %d\n", sized.Len())
+	}
+}
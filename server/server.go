@@ -0,0 +1,432 @@
+// Package server exposes the scraper as a JSON HTTP API.
+//
+// Endpoints mirror the Client methods and query parameters map to the
+// option structs:
+//
+//	GET /apps/{id}                 lang, country
+//	GET /apps/{id}/reviews         lang, country, sort, count, token, score
+//	GET /apps/{id}/similar         lang, country, full
+//	GET /apps/{id}/permissions     lang, country, short
+//	GET /apps/{id}/datasafety      lang, country
+//	GET /developers/{id}           lang, country, num, full
+//	GET /search                    term, lang, country, num, all, price, full
+//	GET /list                      collection, category, age, lang, country, num, full
+//	GET /suggest                   term, lang, country
+//	GET /categories                lang, country
+//	GET /health
+//	GET /metrics
+//
+// Identical concurrent requests share one upstream call, and successful
+// responses are cached for Config.CacheTTL. Data returned together with an
+// error, such as a region-restricted app, is served with the error in the
+// X-Error header and never cached. Searches return at most
+// Config.MaxSearchResults results, including with all=true.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+// Source is the part of *googleplayscraper.Client the server uses
+type Source interface {
+	App(ctx context.Context, appID string, opts gplay.AppOptions) (*gplay.App, error)
+	Reviews(ctx context.Context, appID string, opts gplay.ReviewOptions) (*gplay.ReviewsResult, error)
+	Similar(ctx context.Context, opts gplay.SimilarOptions) ([]gplay.SearchResult, error)
+	Permissions(ctx context.Context, opts gplay.PermissionsOptions) ([]gplay.Permission, error)
+	DataSafety(ctx context.Context, opts gplay.DataSafetyOptions) (*gplay.DataSafety, error)
+	Developer(ctx context.Context, opts gplay.DeveloperOptions) ([]gplay.SearchResult, error)
+	Search(ctx context.Context, opts gplay.SearchOptions) ([]gplay.SearchResult, error)
+	List(ctx context.Context, opts gplay.ListOptions) ([]gplay.SearchResult, error)
+	Suggest(ctx context.Context, opts gplay.SuggestOptions) ([]string, error)
+	Categories(ctx context.Context, opts gplay.CategoriesOptions) ([]gplay.Category, error)
+}
+
+// Config configures a Server
+type Config struct {
+	CacheTTL        time.Duration // How long successful responses are reused; zero disables caching
+	MaxCacheEntries int           // Size of the default cache (default 1000)
+
+	// Cache stores encoded responses. Default gplay.NewMemoryCache(MaxCacheEntries).
+	Cache gplay.Cache
+
+	// MaxSearchResults caps num and all=true on /search, since each page of
+	// results is another upstream call. Default 500.
+	MaxSearchResults int
+}
+
+// Server serves the JSON API. It implements http.Handler.
+type Server struct {
+	src      Source
+	mux      *http.ServeMux
+	cache    gplay.Cache
+	cacheTTL time.Duration
+	group    group
+	metrics  *metrics
+	started  time.Time
+
+	maxSearchResults int
+}
+
+// New creates a server backed by src
+func New(src Source, cfg Config) *Server {
+	if cfg.Cache == nil {
+		cfg.Cache = gplay.NewMemoryCache(cfg.MaxCacheEntries)
+	}
+	if cfg.MaxSearchResults <= 0 {
+		cfg.MaxSearchResults = 500
+	}
+	s := &Server{
+		src:      src,
+		mux:      http.NewServeMux(),
+		cache:    cfg.Cache,
+		cacheTTL: cfg.CacheTTL,
+		metrics:  newMetrics(),
+		started:  time.Now(),
+
+		maxSearchResults: cfg.MaxSearchResults,
+	}
+
+	s.mux.Handle("GET /apps/{id}", s.endpoint("app", s.app))
+	s.mux.Handle("GET /apps/{id}/reviews", s.endpoint("reviews", s.reviews))
+	s.mux.Handle("GET /apps/{id}/similar", s.endpoint("similar", s.similar))
+	s.mux.Handle("GET /apps/{id}/permissions", s.endpoint("permissions", s.permissions))
+	s.mux.Handle("GET /apps/{id}/datasafety", s.endpoint("datasafety", s.dataSafety))
+	s.mux.Handle("GET /developers/{id}", s.endpoint("developer", s.developer))
+	s.mux.Handle("GET /search", s.endpoint("search", s.search))
+	s.mux.Handle("GET /list", s.endpoint("list", s.list))
+	s.mux.Handle("GET /suggest", s.endpoint("suggest", s.suggest))
+	s.mux.Handle("GET /categories", s.endpoint("categories", s.categories))
+	s.mux.HandleFunc("GET /health", s.health)
+	s.mux.HandleFunc("GET /metrics", s.serveMetrics)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// fetchFunc performs the upstream call for a validated request
+type fetchFunc func(ctx context.Context) (any, error)
+
+// badRequest marks parameter errors, which are reported as 400
+type badRequest struct{ msg string }
+
+func (e badRequest) Error() string { return e.msg }
+
+// endpoint wraps a handler with caching, coalescing, metrics and JSON encoding.
+// prepare validates the request and returns the upstream call to make.
+func (s *Server) endpoint(name string, prepare func(r *http.Request) (fetchFunc, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.metrics.add(func(m *metrics) { m.requests[name]++ })
+
+		fetch, err := prepare(r)
+		if err != nil {
+			s.reply(w, name, errorResponse(http.StatusBadRequest, err))
+			return
+		}
+
+		key := "server " + r.URL.Path + "?" + r.URL.Query().Encode()
+		if s.cacheTTL > 0 {
+			if body, ok := s.cache.Get(key); ok {
+				s.metrics.add(func(m *metrics) { m.hits++ })
+				w.Header().Set("X-Cache", "hit")
+				s.reply(w, name, response{status: http.StatusOK, body: body})
+				return
+			}
+		}
+		s.metrics.add(func(m *metrics) { m.misses++ })
+
+		resp, shared := s.group.do(key, func() response {
+			s.metrics.add(func(m *metrics) { m.upstream++ })
+			// The call may be shared, so it must outlive the request that started it
+			v, err := fetch(context.WithoutCancel(r.Context()))
+			resp := encode(v, err)
+			if resp.status == http.StatusOK && resp.err == "" && s.cacheTTL > 0 {
+				s.cache.Set(key, resp.body, s.cacheTTL)
+			}
+			return resp
+		})
+		if shared {
+			s.metrics.add(func(m *metrics) { m.coalesced++ })
+		}
+		w.Header().Set("X-Cache", "miss")
+		s.reply(w, name, resp)
+	})
+}
+
+func (s *Server) reply(w http.ResponseWriter, name string, resp response) {
+	if resp.status >= 400 {
+		s.metrics.add(func(m *metrics) { m.errors[name]++ })
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if resp.err != "" {
+		w.Header().Set("X-Error", resp.err)
+	}
+	w.WriteHeader(resp.status)
+	w.Write(resp.body)
+}
+
+// encode turns an upstream result into a response.
+// A value returned together with an error (e.g. a region-restricted app) is
+// still served, carrying the error for the X-Error header.
+func encode(v any, err error) response {
+	if err != nil && isNil(v) {
+		return errorResponse(statusFor(err), err)
+	}
+	body, merr := json.Marshal(v)
+	if merr != nil {
+		return errorResponse(http.StatusInternalServerError, merr)
+	}
+	resp := response{status: http.StatusOK, body: append(body, '\n')}
+	if err != nil {
+		resp.err = err.Error()
+	}
+	return resp
+}
+
+// isNil reports whether v is nil or a typed nil pointer, slice or map
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func errorResponse(status int, err error) response {
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return response{status: status, body: append(body, '\n')}
+}
+
+// statusFor maps scraper errors to HTTP status codes
+func statusFor(err error) int {
+	var br badRequest
+	switch {
+	case errors.As(err, &br):
+		return http.StatusBadRequest
+	case errors.Is(err, gplay.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, gplay.ErrRegionRestricted), errors.Is(err, gplay.ErrIncompatible), errors.Is(err, gplay.ErrUnavailable):
+		return http.StatusUnavailableForLegalReasons
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	var se *gplay.StatusError
+	if errors.As(err, &se) && se.StatusCode == http.StatusNotFound {
+		return http.StatusNotFound
+	}
+	return http.StatusBadGateway
+}
+
+// params reads typed query parameters and remembers the first error
+type params struct {
+	q   url.Values
+	err error
+}
+
+func newParams(r *http.Request) *params {
+	return &params{q: r.URL.Query()}
+}
+
+func (p *params) str(name, def string) string {
+	if v := p.q.Get(name); v != "" {
+		return v
+	}
+	return def
+}
+
+func (p *params) required(name string) string {
+	v := p.q.Get(name)
+	if v == "" && p.err == nil {
+		p.err = badRequest{fmt.Sprintf("%s is required", name)}
+	}
+	return v
+}
+
+func (p *params) int(name string, def int) int {
+	v := p.q.Get(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil && p.err == nil {
+		p.err = badRequest{fmt.Sprintf("%s: invalid integer %q", name, v)}
+	}
+	return n
+}
+
+func (p *params) bool(name string) bool {
+	v := p.q.Get(name)
+	if v == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil && p.err == nil {
+		p.err = badRequest{fmt.Sprintf("%s: invalid boolean %q", name, v)}
+	}
+	return b
+}
+
+var sorts = map[string]gplay.Sort{
+	"newest":      gplay.SortNewest,
+	"rating":      gplay.SortRating,
+	"helpfulness": gplay.SortHelpfulness,
+}
+
+func (s *Server) app(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	appID := r.PathValue("id")
+	opts := gplay.AppOptions{Lang: p.str("lang", ""), Country: p.str("country", "")}
+	return func(ctx context.Context) (any, error) {
+		return s.src.App(ctx, appID, opts)
+	}, p.err
+}
+
+func (s *Server) reviews(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	appID := r.PathValue("id")
+	sortName := p.str("sort", "newest")
+	sort, ok := sorts[sortName]
+	if !ok {
+		return nil, badRequest{fmt.Sprintf("sort: unknown value %q", sortName)}
+	}
+	opts := gplay.ReviewOptions{
+		Lang:        p.str("lang", ""),
+		Country:     p.str("country", ""),
+		Sort:        sort,
+		Count:       p.int("count", 150),
+		NextToken:   p.str("token", ""),
+		FilterScore: p.int("score", 0),
+	}
+	return func(ctx context.Context) (any, error) {
+		return s.src.Reviews(ctx, appID, opts)
+	}, p.err
+}
+
+func (s *Server) similar(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	opts := gplay.SimilarOptions{
+		AppID:      r.PathValue("id"),
+		Lang:       p.str("lang", ""),
+		Country:    p.str("country", ""),
+		FullDetail: p.bool("full"),
+	}
+	return func(ctx context.Context) (any, error) {
+		return s.src.Similar(ctx, opts)
+	}, p.err
+}
+
+func (s *Server) permissions(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	opts := gplay.PermissionsOptions{
+		AppID:   r.PathValue("id"),
+		Lang:    p.str("lang", ""),
+		Country: p.str("country", ""),
+		Short:   p.bool("short"),
+	}
+	return func(ctx context.Context) (any, error) {
+		return s.src.Permissions(ctx, opts)
+	}, p.err
+}
+
+func (s *Server) dataSafety(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	opts := gplay.DataSafetyOptions{AppID: r.PathValue("id"), Lang: p.str("lang", ""), Country: p.str("country", "")}
+	return func(ctx context.Context) (any, error) {
+		return s.src.DataSafety(ctx, opts)
+	}, p.err
+}
+
+func (s *Server) developer(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	opts := gplay.DeveloperOptions{
+		DevID:      r.PathValue("id"),
+		Lang:       p.str("lang", ""),
+		Country:    p.str("country", ""),
+		Num:        p.int("num", 0),
+		FullDetail: p.bool("full"),
+	}
+	return func(ctx context.Context) (any, error) {
+		return s.src.Developer(ctx, opts)
+	}, p.err
+}
+
+func (s *Server) search(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	opts := gplay.SearchOptions{
+		Term:       p.required("term"),
+		Lang:       p.str("lang", ""),
+		Country:    p.str("country", ""),
+		Num:        p.int("num", 0),
+		All:        p.bool("all"),
+		Price:      p.str("price", ""),
+		FullDetail: p.bool("full"),
+	}
+	// Follow pagination only up to the cap
+	if opts.All || opts.Num > s.maxSearchResults {
+		opts.All, opts.Num = false, s.maxSearchResults
+	}
+	return func(ctx context.Context) (any, error) {
+		return s.src.Search(ctx, opts)
+	}, p.err
+}
+
+func (s *Server) list(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	opts := gplay.ListOptions{
+		Collection: gplay.Collection(strings.ToUpper(p.str("collection", ""))),
+		Category:   gplay.Category(strings.ToUpper(p.str("category", ""))),
+		Age:        gplay.Age(strings.ToUpper(p.str("age", ""))),
+		Lang:       p.str("lang", ""),
+		Country:    p.str("country", ""),
+		Num:        p.int("num", 0),
+		FullDetail: p.bool("full"),
+	}
+	return func(ctx context.Context) (any, error) {
+		return s.src.List(ctx, opts)
+	}, p.err
+}
+
+func (s *Server) suggest(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	opts := gplay.SuggestOptions{Term: p.required("term"), Lang: p.str("lang", ""), Country: p.str("country", "")}
+	return func(ctx context.Context) (any, error) {
+		return s.src.Suggest(ctx, opts)
+	}, p.err
+}
+
+func (s *Server) categories(r *http.Request) (fetchFunc, error) {
+	p := newParams(r)
+	opts := gplay.CategoriesOptions{Lang: p.str("lang", ""), Country: p.str("country", "")}
+	return func(ctx context.Context) (any, error) {
+		return s.src.Categories(ctx, opts)
+	}, p.err
+}
+
+func (s *Server) health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"status": "ok",
+		"uptime": time.Since(s.started).Round(time.Second).String(),
+	})
+}
+
+func (s *Server) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.metrics.writeTo(w, s.cache)
+}
@@ -0,0 +1,335 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+	"github.com/kryuchenko/google-play-scraper/playtest"
+)
+
+// fakeSource records calls and serves canned data
+type fakeSource struct {
+	calls   atomic.Int64
+	release chan struct{} // When set, App blocks until it is closed
+
+	mu          sync.Mutex
+	searchOpts  gplay.SearchOptions
+	reviewsOpts gplay.ReviewOptions
+}
+
+func (f *fakeSource) App(ctx context.Context, appID string, opts gplay.AppOptions) (*gplay.App, error) {
+	f.calls.Add(1)
+	if f.release != nil {
+		<-f.release
+	}
+	switch appID {
+	case "com.removed":
+		return nil, &gplay.UnavailableError{AppID: appID, Availability: gplay.AvailabilityNotFound}
+	case "com.restricted":
+		app := &gplay.App{AppID: appID, Availability: gplay.AvailabilityRegionRestricted}
+		return app, &gplay.UnavailableError{AppID: appID, Availability: gplay.AvailabilityRegionRestricted}
+	case "com.broken":
+		return nil, &gplay.StatusError{StatusCode: http.StatusInternalServerError}
+	}
+	return &gplay.App{AppID: appID, Title: "Example " + opts.Lang}, nil
+}
+
+func (f *fakeSource) Reviews(ctx context.Context, appID string, opts gplay.ReviewOptions) (*gplay.ReviewsResult, error) {
+	f.calls.Add(1)
+	f.mu.Lock()
+	f.reviewsOpts = opts
+	f.mu.Unlock()
+	return &gplay.ReviewsResult{Reviews: []gplay.Review{{ID: "r1"}}, NextToken: "next"}, nil
+}
+
+func (f *fakeSource) Similar(ctx context.Context, opts gplay.SimilarOptions) ([]gplay.SearchResult, error) {
+	return []gplay.SearchResult{{AppID: "com.similar"}}, nil
+}
+
+func (f *fakeSource) Permissions(ctx context.Context, opts gplay.PermissionsOptions) ([]gplay.Permission, error) {
+	return []gplay.Permission{{Type: "Camera", Permission: "take pictures"}}, nil
+}
+
+func (f *fakeSource) DataSafety(ctx context.Context, opts gplay.DataSafetyOptions) (*gplay.DataSafety, error) {
+	return &gplay.DataSafety{PrivacyPolicyURL: "https://example.com/privacy"}, nil
+}
+
+func (f *fakeSource) Developer(ctx context.Context, opts gplay.DeveloperOptions) ([]gplay.SearchResult, error) {
+	return []gplay.SearchResult{{AppID: "com.dev", Developer: opts.DevID}}, nil
+}
+
+func (f *fakeSource) Search(ctx context.Context, opts gplay.SearchOptions) ([]gplay.SearchResult, error) {
+	f.calls.Add(1)
+	f.mu.Lock()
+	f.searchOpts = opts
+	f.mu.Unlock()
+	return []gplay.SearchResult{{AppID: "com.found", Title: opts.Term}}, nil
+}
+
+func (f *fakeSource) List(ctx context.Context, opts gplay.ListOptions) ([]gplay.SearchResult, error) {
+	return []gplay.SearchResult{{AppID: "com.top"}}, nil
+}
+
+func (f *fakeSource) Suggest(ctx context.Context, opts gplay.SuggestOptions) ([]string, error) {
+	return []string{opts.Term + " app"}, nil
+}
+
+func (f *fakeSource) Categories(ctx context.Context, opts gplay.CategoriesOptions) ([]gplay.Category, error) {
+	return gplay.AllCategories, nil
+}
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestEndpoints(t *testing.T) {
+	srv := playtest.NewServer()
+	defer srv.Close()
+	srv.AddApp(gplay.App{AppID: "com.example", Title: "Maps", Developer: "Google LLC", DeveloperID: "Google LLC"})
+	srv.AddApp(gplay.App{AppID: "com.similar", Title: "Atlas", Developer: "Google LLC", DeveloperID: "Google LLC"})
+	srv.SetSimilar("com.example", "com.similar")
+	srv.SetTopChart(gplay.CollectionTopPaid, gplay.CategoryCommunication, "com.example")
+	srv.SetPermissions("com.example", gplay.Permission{Type: "Camera", Permission: "take pictures"})
+	srv.SetDataSafety("com.example", gplay.DataSafety{PrivacyPolicyURL: "https://example.com/privacy"})
+	s := New(srv.Client(), Config{})
+	paths := []string{
+		"/apps/com.example",
+		"/apps/com.example/reviews",
+		"/apps/com.example/similar",
+		"/apps/com.example/permissions",
+		"/apps/com.example/datasafety",
+		"/developers/Google%20LLC",
+		"/search?term=maps",
+		"/list?collection=top_paid&category=communication",
+		"/suggest?term=maps",
+		"/categories",
+		"/health",
+	}
+	for _, path := range paths {
+		rec := get(t, s, path)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", path, rec.Code, rec.Body)
+			continue
+		}
+		if !json.Valid(rec.Body.Bytes()) {
+			t.Errorf("%s: invalid JSON: %s", path, rec.Body)
+		}
+		if body := strings.TrimSpace(rec.Body.String()); body == "null" || body == "[]" || body == "{}" {
+			t.Errorf("%s: empty response", path)
+		}
+	}
+}
+
+func TestQueryParameters(t *testing.T) {
+	src := &fakeSource{}
+	s := New(src, Config{})
+
+	if rec := get(t, s, "/search?term=maps&num=5&price=free&full=true&lang=de"); rec.Code != http.StatusOK {
+		t.Fatalf("search: status %d", rec.Code)
+	}
+	want := gplay.SearchOptions{Term: "maps", Lang: "de", Num: 5, Price: "free", FullDetail: true}
+	if src.searchOpts != want {
+		t.Errorf("search options: got %+v, want %+v", src.searchOpts, want)
+	}
+
+	for _, path := range []string{"/search?term=maps&all=true", "/search?term=maps&num=100000"} {
+		if rec := get(t, s, path); rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d", path, rec.Code)
+		}
+		if src.searchOpts.All || src.searchOpts.Num != 500 {
+			t.Errorf("%s: got %+v, want all capped at 500", path, src.searchOpts)
+		}
+	}
+
+	if rec := get(t, s, "/apps/com.example/reviews?sort=rating&count=20&score=1&token=abc"); rec.Code != http.StatusOK {
+		t.Fatalf("reviews: status %d", rec.Code)
+	}
+	got := src.reviewsOpts
+	if got.Sort != gplay.SortRating || got.Count != 20 || got.FilterScore != 1 || got.NextToken != "abc" {
+		t.Errorf("review options: got %+v", got)
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	s := New(&fakeSource{}, Config{})
+	for _, path := range []string{
+		"/search",
+		"/search?term=maps&num=many",
+		"/search?term=maps&full=maybe",
+		"/apps/com.example/reviews?sort=oldest",
+		"/suggest",
+	} {
+		rec := get(t, s, path)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %d, want 400", path, rec.Code)
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+			t.Errorf("%s: expected JSON error body, got %s", path, rec.Body)
+		}
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	s := New(&fakeSource{}, Config{})
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/apps/com.removed", http.StatusNotFound},
+		{"/apps/com.restricted", http.StatusOK}, // Partial listing is still served
+		{"/apps/com.broken", http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		if rec := get(t, s, tt.path); rec.Code != tt.code {
+			t.Errorf("%s: got status %d, want %d", tt.path, rec.Code, tt.code)
+		}
+	}
+	if rec := get(t, s, "/apps/com.restricted"); !strings.Contains(rec.Body.String(), `"availability":"region_restricted"`) {
+		t.Errorf("restricted app body: %s", rec.Body)
+	}
+}
+
+func TestCaching(t *testing.T) {
+	src := &fakeSource{}
+	s := New(src, Config{CacheTTL: time.Minute})
+
+	first := get(t, s, "/apps/com.example?lang=en")
+	second := get(t, s, "/apps/com.example?lang=en")
+	if src.calls.Load() != 1 {
+		t.Errorf("expected one upstream call, got %d", src.calls.Load())
+	}
+	if first.Header().Get("X-Cache") != "miss" || second.Header().Get("X-Cache") != "hit" {
+		t.Errorf("X-Cache: got %q then %q", first.Header().Get("X-Cache"), second.Header().Get("X-Cache"))
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Error("cached body differs")
+	}
+
+	get(t, s, "/apps/com.example?lang=de")
+	if src.calls.Load() != 2 {
+		t.Errorf("different parameters should miss the cache, got %d calls", src.calls.Load())
+	}
+
+	get(t, s, "/apps/com.removed")
+	get(t, s, "/apps/com.removed")
+	if src.calls.Load() != 4 {
+		t.Errorf("errors should not be cached, got %d calls", src.calls.Load())
+	}
+}
+
+func TestPartialResultsAreNotCached(t *testing.T) {
+	src := &fakeSource{}
+	cache := gplay.NewMemoryCache(10)
+	s := New(src, Config{CacheTTL: time.Minute, Cache: cache})
+
+	for i := 0; i < 2; i++ {
+		rec := get(t, s, "/apps/com.restricted")
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Header().Get("X-Error"), "country") {
+			t.Errorf("restricted app: status %d, X-Error %q", rec.Code, rec.Header().Get("X-Error"))
+		}
+	}
+	if src.calls.Load() != 2 || cache.Len() != 0 {
+		t.Errorf("responses with an error should not be cached: %d calls, %d entries", src.calls.Load(), cache.Len())
+	}
+
+	get(t, s, "/apps/com.example")
+	if rec := get(t, s, "/apps/com.example"); rec.Header().Get("X-Cache") != "hit" || rec.Header().Get("X-Error") != "" {
+		t.Errorf("complete response: X-Cache %q, X-Error %q", rec.Header().Get("X-Cache"), rec.Header().Get("X-Error"))
+	}
+	if cache.Len() != 1 {
+		t.Errorf("expected the configured cache to hold one entry, got %d", cache.Len())
+	}
+}
+
+func TestCoalescing(t *testing.T) {
+	src := &fakeSource{release: make(chan struct{})}
+	s := New(src, Config{})
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	const n = 5
+	var wg sync.WaitGroup
+	bodies := make([]string, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := http.Get(server.URL + "/apps/com.example")
+			if err != nil {
+				t.Errorf("GET: %v", err)
+				return
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			bodies[i] = string(body)
+		}(i)
+	}
+
+	// Wait until every request is either running the call or waiting on it
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.metrics.mu.Lock()
+		waiting := s.metrics.requests["app"]
+		s.metrics.mu.Unlock()
+		if waiting == n || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(src.release)
+	wg.Wait()
+
+	if calls := src.calls.Load(); calls != 1 {
+		t.Errorf("expected concurrent identical requests to share one call, got %d", calls)
+	}
+	for i := 1; i < n; i++ {
+		if bodies[i] != bodies[0] {
+			t.Errorf("response %d differs: %q vs %q", i, bodies[i], bodies[0])
+		}
+	}
+}
+
+func TestGroupSharesErrors(t *testing.T) {
+	var g group
+	resp, shared := g.do("k", func() response { return errorResponse(http.StatusBadGateway, errors.New("boom")) })
+	if shared || resp.status != http.StatusBadGateway {
+		t.Errorf("got %+v, shared=%v", resp, shared)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	s := New(&fakeSource{}, Config{CacheTTL: time.Minute})
+	get(t, s, "/apps/com.example")
+	get(t, s, "/apps/com.example")
+	get(t, s, "/search")
+
+	rec := get(t, s, "/metrics")
+	body := rec.Body.String()
+	for _, want := range []string{
+		`gplay_requests_total{endpoint="app"} 2`,
+		`gplay_requests_total{endpoint="search"} 1`,
+		`gplay_errors_total{endpoint="search"} 1`,
+		"gplay_upstream_requests_total 1",
+		"gplay_cache_hits_total 1",
+		"gplay_cache_entries 1",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package googleplayscraper
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -55,39 +54,25 @@ func (c *Client) Similar(ctx context.Context, opts SimilarOptions) ([]SearchResu
 		return nil, fmt.Errorf("cluster request failed: %w", err)
 	}
 
-	results, err := parseSimilarPage(clusterBody)
+	rep := c.newReport(EndpointCluster, fullClusterURL)
+	results, err := parseSimilarPage(clusterBody, rep)
 	if err != nil {
 		return nil, err
 	}
+	if err := c.sendReport(rep, len(results)); err != nil {
+		return nil, err
+	}
 
 	// Fetch full details if requested
 	if opts.FullDetail {
 		return c.enrichSearchResults(ctx, results, opts.Lang, opts.Country)
 	}
 
-	return results, nil
+	return c.rawResults(results), nil
 }
 
 func findSimilarCluster(body []byte) (string, error) {
-	html := string(body)
-
-	// Find data blocks
-	dataBlocks := make(map[string]interface{})
-	matches := scriptDataRegex.FindAllStringSubmatch(html, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 {
-			continue
-		}
-		key := match[1]
-		dataStr := strings.TrimSpace(match[2])
-
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			continue
-		}
-		dataBlocks[key] = data
-	}
+	dataBlocks := parseDataBlocks(body)
 
 	// Look for clusters with "Similar" in title
 	// Clusters are typically in ds:7 or ds:8, path [1][1]
@@ -131,47 +116,28 @@ func findSimilarCluster(body []byte) (string, error) {
 	return "", nil
 }
 
-func parseSimilarPage(body []byte) ([]SearchResult, error) {
-	html := string(body)
-
-	// Find data blocks
-	dataBlocks := make(map[string]interface{})
-	matches := scriptDataRegex.FindAllStringSubmatch(html, -1)
-
-	for _, match := range matches {
-		if len(match) < 3 {
-			continue
-		}
-		key := match[1]
-		dataStr := strings.TrimSpace(match[2])
-
-		var data interface{}
-		if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
-			continue
-		}
-		dataBlocks[key] = data
-	}
+func parseSimilarPage(body []byte, rep *ParseReport) ([]SearchResult, error) {
+	dataBlocks := parseDataBlocks(body)
 
 	// Apps in ds:3 -> [0][1][0][21][0]
 	ds3, ok := dataBlocks["ds:3"]
 	if !ok {
+		rep.mismatch("ds:3", "data block not found")
 		return nil, nil
 	}
 
-	appsSection := getPath(ds3, 0, 1, 0, 21, 0)
-	if appsSection == nil {
-		return nil, nil
-	}
-
-	apps, ok := appsSection.([]interface{})
+	apps, ok := getPath(ds3, 0, 1, 0, 21, 0).([]interface{})
 	if !ok {
+		rep.mismatch("ds:3[0][1][0][21][0]", "apps not found")
 		return nil, nil
 	}
 
 	var results []SearchResult
-	for _, app := range apps {
-		result := parseSimilarApp(app)
+	for i, app := range apps {
+		rep.at("ds:3[0][1][0][21][0][%d]", i)
+		result := parseSimilarApp(app, rep)
 		if result.AppID != "" {
+			result.Raw.Blocks = dataBlocks
 			results = append(results, result)
 		}
 	}
@@ -179,40 +145,9 @@ func parseSimilarPage(body []byte) ([]SearchResult, error) {
 	return results, nil
 }
 
-func parseSimilarApp(item interface{}) SearchResult {
-	arr, ok := item.([]interface{})
-	if !ok {
+func parseSimilarApp(item interface{}, rep *ParseReport) SearchResult {
+	if _, ok := item.([]interface{}); !ok {
 		return SearchResult{}
 	}
-
-	result := SearchResult{}
-
-	// appId: [0][0]
-	if v := getPath(arr, 0, 0); v != nil {
-		result.AppID = toString(v)
-	}
-	// title: [3]
-	if v := getPath(arr, 3); v != nil {
-		result.Title = toString(v)
-	}
-	// icon: [1][3][2]
-	if v := getPath(arr, 1, 3, 2); v != nil {
-		result.Icon = toString(v)
-	}
-	// developer: [14]
-	if v := getPath(arr, 14); v != nil {
-		result.Developer = toString(v)
-	}
-	// score: [4][1]
-	if v := getPath(arr, 4, 1); v != nil {
-		result.Score = toFloat64(v)
-	}
-
-	result.Free = true
-
-	if result.AppID != "" {
-		result.URL = fmt.Sprintf("%s/store/apps/details?id=%s", BaseURL, result.AppID)
-	}
-
-	return result
+	return parseCard(item, cardFields, rep)
 }
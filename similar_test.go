@@ -160,7 +160,7 @@ func TestFindSimilarCluster(t *testing.T) {
 
 func TestParseSimilarPage(t *testing.T) {
 	// Empty body
-	res, err := parseSimilarPage([]byte{})
+	res, err := parseSimilarPage([]byte{}, nil)
 	if err != nil {
 		// Should return nil
 	}
@@ -169,7 +169,7 @@ func TestParseSimilarPage(t *testing.T) {
 	}
 
 	// Missing ds:3
-	res, err = parseSimilarPage([]byte(`<html></html>`))
+	res, err = parseSimilarPage([]byte(`<html></html>`), nil)
 	if len(res) != 0 {
 		t.Error("expected 0 results")
 	}
@@ -178,7 +178,7 @@ func TestParseSimilarPage(t *testing.T) {
 	malformed := `
 		<script>AF_initDataCallback({key: 'ds:3', isError: false , hash: '1', data: []});</script>
 	`
-	res, err = parseSimilarPage([]byte(malformed))
+	res, err = parseSimilarPage([]byte(malformed), nil)
 	if len(res) != 0 {
 		t.Error("expected 0 results")
 	}
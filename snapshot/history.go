@@ -0,0 +1,64 @@
+package snapshot
+
+import (
+	"context"
+	"time"
+)
+
+// ScorePoint is an app's rating at a point in time
+type ScorePoint struct {
+	Time    time.Time `json:"time"`
+	Score   float64   `json:"score"`
+	Ratings int       `json:"ratings"`
+}
+
+// VersionChange is the first snapshot that saw a version different from the one before
+type VersionChange struct {
+	Time    time.Time `json:"time"`
+	Version string    `json:"version"`
+	Updated int64     `json:"updated"` // Update timestamp reported by Google Play
+}
+
+// ScoreHistory returns the score of every app snapshot in [from, to).
+// Zero times leave that end of the range open.
+func ScoreHistory(ctx context.Context, s Store, appID string, from, to time.Time) ([]ScorePoint, error) {
+	records, err := s.List(ctx, appID, Query{Kind: KindApp, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]ScorePoint, 0, len(records))
+	for _, rec := range records {
+		app, err := rec.App()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, ScorePoint{Time: rec.Time, Score: app.Score, Ratings: app.Ratings})
+	}
+	return points, nil
+}
+
+// VersionHistory returns each version change in order. A rollback is a
+// change too, so a version can appear more than once.
+func VersionHistory(ctx context.Context, s Store, appID string) ([]VersionChange, error) {
+	records, err := s.List(ctx, appID, Query{Kind: KindApp})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []VersionChange
+	for _, rec := range records {
+		app, err := rec.App()
+		if err != nil {
+			return nil, err
+		}
+		if app.Version == "" {
+			continue
+		}
+		if n := len(changes); n > 0 && changes[n-1].Version == app.Version {
+			continue
+		}
+		changes = append(changes, VersionChange{Time: rec.Time, Version: app.Version, Updated: app.Updated})
+	}
+	return changes, nil
+}
@@ -0,0 +1,159 @@
+// Package snapshot keeps a timestamped history of app metadata.
+//
+// Records are written through the Store interface; FileStore keeps an
+// append-only JSONL file per app and MemoryStore keeps everything in memory.
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+// Kind identifies what a record holds
+type Kind string
+
+const (
+	KindApp         Kind = "app"
+	KindDataSafety  Kind = "datasafety"
+	KindPermissions Kind = "permissions"
+)
+
+// Record is a single timestamped snapshot
+type Record struct {
+	AppID string          `json:"appId"`
+	Kind  Kind            `json:"kind"`
+	Time  time.Time       `json:"time"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Query selects records from a store. Zero values match everything.
+type Query struct {
+	Kind Kind
+	From time.Time // Inclusive
+	To   time.Time // Exclusive
+}
+
+func (q Query) matches(r Record) bool {
+	if q.Kind != "" && r.Kind != q.Kind {
+		return false
+	}
+	if !q.From.IsZero() && r.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !r.Time.Before(q.To) {
+		return false
+	}
+	return true
+}
+
+// Store persists snapshot records
+type Store interface {
+	// Append adds a record to the app's history
+	Append(ctx context.Context, rec Record) error
+	// List returns an app's records matching q, oldest first
+	List(ctx context.Context, appID string, q Query) ([]Record, error)
+	// Apps returns the IDs of all apps with at least one record
+	Apps(ctx context.Context) ([]string, error)
+}
+
+// NewRecord encodes v as a record taken at t
+func NewRecord(appID string, kind Kind, t time.Time, v interface{}) (Record, error) {
+	if appID == "" {
+		return Record{}, fmt.Errorf("appID is required")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Record{}, fmt.Errorf("encode %s snapshot: %w", kind, err)
+	}
+	return Record{AppID: appID, Kind: kind, Time: t.UTC(), Data: data}, nil
+}
+
+// App decodes an app record
+func (r Record) App() (*gplay.App, error) {
+	var app gplay.App
+	if err := r.decode(KindApp, &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// DataSafety decodes a data safety record
+func (r Record) DataSafety() (*gplay.DataSafety, error) {
+	var ds gplay.DataSafety
+	if err := r.decode(KindDataSafety, &ds); err != nil {
+		return nil, err
+	}
+	return &ds, nil
+}
+
+// Permissions decodes a permissions record
+func (r Record) Permissions() ([]gplay.Permission, error) {
+	var perms []gplay.Permission
+	if err := r.decode(KindPermissions, &perms); err != nil {
+		return nil, err
+	}
+	return perms, nil
+}
+
+func (r Record) decode(kind Kind, v interface{}) error {
+	if r.Kind != kind {
+		return fmt.Errorf("record is %s, not %s", r.Kind, kind)
+	}
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		return fmt.Errorf("decode %s snapshot: %w", kind, err)
+	}
+	return nil
+}
+
+// SaveApp records an app snapshot taken at t
+func SaveApp(ctx context.Context, s Store, app *gplay.App, t time.Time) error {
+	rec, err := NewRecord(app.AppID, KindApp, t, app)
+	if err != nil {
+		return err
+	}
+	return s.Append(ctx, rec)
+}
+
+// SaveDataSafety records a data safety snapshot taken at t
+func SaveDataSafety(ctx context.Context, s Store, appID string, ds *gplay.DataSafety, t time.Time) error {
+	rec, err := NewRecord(appID, KindDataSafety, t, ds)
+	if err != nil {
+		return err
+	}
+	return s.Append(ctx, rec)
+}
+
+// SavePermissions records a permissions snapshot taken at t
+func SavePermissions(ctx context.Context, s Store, appID string, perms []gplay.Permission, t time.Time) error {
+	rec, err := NewRecord(appID, KindPermissions, t, perms)
+	if err != nil {
+		return err
+	}
+	return s.Append(ctx, rec)
+}
+
+// LatestStore is a Store that finds an app's most recent record of a kind
+// without listing its whole history. FileStore implements it.
+type LatestStore interface {
+	Store
+	Latest(ctx context.Context, appID string, kind Kind) (*Record, error)
+}
+
+// Latest returns the most recent record of the given kind, or nil if there is none
+func Latest(ctx context.Context, s Store, appID string, kind Kind) (*Record, error) {
+	if ls, ok := s.(LatestStore); ok {
+		return ls.Latest(ctx, appID, kind)
+	}
+	records, err := s.List(ctx, appID, Query{Kind: kind})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return &records[len(records)-1], nil
+}
@@ -0,0 +1,176 @@
+package snapshot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	gplay "github.com/kryuchenko/google-play-scraper"
+)
+
+var t0 = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func day(n int) time.Time {
+	return t0.AddDate(0, 0, n)
+}
+
+func seed(t *testing.T, s Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	apps := []gplay.App{
+		{AppID: "com.example", Version: "1.0", Score: 4.0, Ratings: 100},
+		{AppID: "com.example", Version: "1.0", Score: 4.1, Ratings: 120},
+		{AppID: "com.example", Version: "1.1", Score: 4.3, Ratings: 150, Updated: 1767225600},
+	}
+	for i := range apps {
+		if err := SaveApp(ctx, s, &apps[i], day(i)); err != nil {
+			t.Fatalf("SaveApp: %v", err)
+		}
+	}
+	perms := []gplay.Permission{{Type: "Camera", Permission: "take pictures"}}
+	if err := SavePermissions(ctx, s, "com.example", perms, day(1)); err != nil {
+		t.Fatalf("SavePermissions: %v", err)
+	}
+	ds := &gplay.DataSafety{PrivacyPolicyURL: "https://example.com/privacy"}
+	if err := SaveDataSafety(ctx, s, "com.example", ds, day(1)); err != nil {
+		t.Fatalf("SaveDataSafety: %v", err)
+	}
+}
+
+func testStore(t *testing.T, s Store) {
+	seed(t, s)
+	ctx := context.Background()
+
+	all, err := s.List(ctx, "com.example", Query{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("expected 5 records, got %d", len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Time.Before(all[i-1].Time) {
+			t.Error("records should be ordered oldest first")
+		}
+	}
+
+	ranged, err := s.List(ctx, "com.example", Query{Kind: KindApp, From: day(1), To: day(2)})
+	if err != nil {
+		t.Fatalf("List range: %v", err)
+	}
+	if len(ranged) != 1 || !ranged[0].Time.Equal(day(1)) {
+		t.Errorf("range query: got %+v", ranged)
+	}
+
+	scores, err := ScoreHistory(ctx, s, "com.example", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("ScoreHistory: %v", err)
+	}
+	wantScores := []ScorePoint{{day(0), 4.0, 100}, {day(1), 4.1, 120}, {day(2), 4.3, 150}}
+	if !reflect.DeepEqual(scores, wantScores) {
+		t.Errorf("ScoreHistory: got %+v, want %+v", scores, wantScores)
+	}
+
+	versions, err := VersionHistory(ctx, s, "com.example")
+	if err != nil {
+		t.Fatalf("VersionHistory: %v", err)
+	}
+	wantVersions := []VersionChange{{day(0), "1.0", 0}, {day(2), "1.1", 1767225600}}
+	if !reflect.DeepEqual(versions, wantVersions) {
+		t.Errorf("VersionHistory: got %+v, want %+v", versions, wantVersions)
+	}
+
+	latest, err := Latest(ctx, s, "com.example", KindPermissions)
+	if err != nil || latest == nil {
+		t.Fatalf("Latest: %v, %v", latest, err)
+	}
+	perms, err := latest.Permissions()
+	if err != nil || len(perms) != 1 || perms[0].Type != "Camera" {
+		t.Errorf("Permissions: got %+v, %v", perms, err)
+	}
+	if _, err := latest.App(); err == nil {
+		t.Error("decoding a permissions record as an app should fail")
+	}
+
+	latest, err = Latest(ctx, s, "com.example", KindDataSafety)
+	if err != nil || latest == nil {
+		t.Fatalf("Latest: %v, %v", latest, err)
+	}
+	if ds, err := latest.DataSafety(); err != nil || ds.PrivacyPolicyURL != "https://example.com/privacy" {
+		t.Errorf("DataSafety: got %+v, %v", ds, err)
+	}
+
+	if none, err := Latest(ctx, s, "com.unknown", KindApp); err != nil || none != nil {
+		t.Errorf("Latest for unknown app: got %+v, %v", none, err)
+	}
+
+	apps, err := s.Apps(ctx)
+	if err != nil || !reflect.DeepEqual(apps, []string{"com.example"}) {
+		t.Errorf("Apps: got %v, %v", apps, err)
+	}
+
+	// A rollback is a version change, and appending after Latest keeps it current
+	if err := SaveApp(ctx, s, &gplay.App{AppID: "com.example", Version: "1.0"}, day(3)); err != nil {
+		t.Fatalf("SaveApp: %v", err)
+	}
+	versions, err = VersionHistory(ctx, s, "com.example")
+	if err != nil || len(versions) != 3 || versions[2].Version != "1.0" || !versions[2].Time.Equal(day(3)) {
+		t.Errorf("VersionHistory after rollback: got %+v, %v", versions, err)
+	}
+	latest, err = Latest(ctx, s, "com.example", KindApp)
+	if err != nil || latest == nil || !latest.Time.Equal(day(3)) {
+		t.Errorf("Latest after append: got %+v, %v", latest, err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	testStore(t, s)
+
+	// A reopened store sees the same history
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	records, err := reopened.List(context.Background(), "com.example", Query{})
+	if err != nil || len(records) != 6 {
+		t.Errorf("reopened store: got %d records, %v", len(records), err)
+	}
+	latest, err := reopened.Latest(context.Background(), "com.example", KindApp)
+	if err != nil || latest == nil || !latest.Time.Equal(day(3)) {
+		t.Errorf("reopened Latest: got %+v, %v", latest, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "com.example.jsonl")); err != nil {
+		t.Errorf("expected JSONL file per app: %v", err)
+	}
+}
+
+func TestFileStoreRejectsPathAppID(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	rec := Record{AppID: "../escape", Kind: KindApp, Time: t0}
+	if err := s.Append(context.Background(), rec); err == nil {
+		t.Error("expected error for appID with path separators")
+	}
+}
+
+func TestNewRecordValidation(t *testing.T) {
+	if _, err := NewRecord("", KindApp, t0, nil); err == nil {
+		t.Error("expected error for empty appID")
+	}
+}
@@ -0,0 +1,225 @@
+package snapshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileStore keeps one append-only JSONL file per app in a directory.
+// It indexes the latest record of each kind the first time an app's latest
+// record is asked for, so it must be the only writer of its directory.
+type FileStore struct {
+	dir    string
+	mu     sync.Mutex
+	latest map[string]map[Kind]Record // App ID -> kind -> latest record
+}
+
+// NewFileStore creates a store in dir, creating the directory if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create store directory: %w", err)
+	}
+	return &FileStore{dir: dir, latest: make(map[string]map[Kind]Record)}, nil
+}
+
+func (s *FileStore) path(appID string) (string, error) {
+	if appID == "" || strings.ContainsAny(appID, `/\`) || strings.Contains(appID, "..") {
+		return "", fmt.Errorf("invalid appID %q", appID)
+	}
+	return filepath.Join(s.dir, appID+".jsonl"), nil
+}
+
+// Append writes rec as a new line of the app's file
+func (s *FileStore) Append(ctx context.Context, rec Record) error {
+	path, err := s.path(rec.AppID)
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open store file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	if index, ok := s.latest[rec.AppID]; ok {
+		indexLatest(index, rec)
+	}
+	return nil
+}
+
+// List reads the app's file and returns matching records, oldest first
+func (s *FileStore) List(ctx context.Context, appID string, q Query) ([]Record, error) {
+	path, err := s.path(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []Record
+	err = s.scan(path, func(rec Record) {
+		if q.matches(rec) {
+			records = append(records, rec)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortRecords(records)
+	return records, nil
+}
+
+// Latest returns the app's most recent record of the given kind, or nil if
+// there is none. Only the first call for an app reads its file.
+func (s *FileStore) Latest(ctx context.Context, appID string, kind Kind) (*Record, error) {
+	path, err := s.path(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, ok := s.latest[appID]
+	if !ok {
+		index = make(map[Kind]Record)
+		if err := s.scan(path, func(rec Record) { indexLatest(index, rec) }); err != nil {
+			return nil, err
+		}
+		s.latest[appID] = index
+	}
+	rec, ok := index[kind]
+	if !ok {
+		return nil, nil
+	}
+	return &rec, nil
+}
+
+// indexLatest keeps rec if it is the newest of its kind; of records with the
+// same time the last one written wins, as in List
+func indexLatest(index map[Kind]Record, rec Record) {
+	if prev, ok := index[rec.Kind]; !ok || !rec.Time.Before(prev.Time) {
+		index[rec.Kind] = rec
+	}
+}
+
+// scan decodes each record of the file at path in order. A missing file has
+// no records. The caller holds s.mu.
+func (s *FileStore) scan(path string, fn func(Record)) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open store file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024) // App records with long descriptions exceed the default
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		fn(rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read store file: %w", err)
+	}
+	return nil
+}
+
+// Apps lists the apps that have a history file
+func (s *FileStore) Apps(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read store directory: %w", err)
+	}
+	var apps []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".jsonl") {
+			apps = append(apps, strings.TrimSuffix(e.Name(), ".jsonl"))
+		}
+	}
+	return apps, nil
+}
+
+// MemoryStore keeps records in memory; useful for tests and short-lived jobs
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string][]Record
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string][]Record)}
+}
+
+// Append adds rec to the app's history
+func (s *MemoryStore) Append(ctx context.Context, rec Record) error {
+	if rec.AppID == "" {
+		return fmt.Errorf("appID is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[rec.AppID] = append(s.records[rec.AppID], rec)
+	return nil
+}
+
+// List returns matching records, oldest first
+func (s *MemoryStore) List(ctx context.Context, appID string, q Query) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []Record
+	for _, rec := range s.records[appID] {
+		if q.matches(rec) {
+			records = append(records, rec)
+		}
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+// Apps lists the apps that have records, sorted by ID
+func (s *MemoryStore) Apps(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	apps := make([]string, 0, len(s.records))
+	for id := range s.records {
+		apps = append(apps, id)
+	}
+	sort.Strings(apps)
+	return apps, nil
+}
+
+func sortRecords(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+}
@@ -0,0 +1,84 @@
+package googleplayscraper
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ParseError reports a response whose structure didn't match what the parser expects
+type ParseError struct {
+	Endpoint Endpoint
+	Source   string // Page URL, or RPC ID for batchexecute responses
+	Path     string // Location in the data, e.g. ds:5[1][2][79][0][0][0] or oCPfdb[0][3]
+	Field    string // Mapped field, empty for structural mismatches
+	Msg      string
+}
+
+func (e *ParseError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("parse %s %s: %s at %s: %s", e.Endpoint, e.Source, e.Field, e.Path, e.Msg)
+	}
+	return fmt.Sprintf("parse %s %s: %s: %s", e.Endpoint, e.Source, e.Path, e.Msg)
+}
+
+// WithStrict makes client methods fail instead of returning plausible but
+// degraded results: structural fallbacks, values of the wrong type and
+// required fields missing from every record become *ParseError errors.
+// Use it for canaries that should notice layout changes.
+func WithStrict() ClientOption {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// mismatch records a structural fallback at path, relative to the current record
+func (r *ParseReport) mismatch(path, format string, args ...interface{}) {
+	if r == nil {
+		return
+	}
+	r.Mismatches = append(r.Mismatches, ParseError{
+		Endpoint: r.Endpoint,
+		Source:   r.Source,
+		Path:     path,
+		Msg:      fmt.Sprintf(format, args...),
+	})
+}
+
+// number records a string at path, relative to the current record, that the
+// lenient converters would silently turn into 0
+func (r *ParseReport) number(field string, v interface{}, path []int) {
+	s, ok := v.(string)
+	if r == nil || !ok {
+		return
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		r.Mismatches = append(r.Mismatches, ParseError{
+			Endpoint: r.Endpoint,
+			Source:   r.Source,
+			Path:     r.loc + pathString(path),
+			Field:    field,
+			Msg:      err.Error(),
+		})
+	}
+}
+
+// Err returns the report's mismatches and drifted fields as joined
+// *ParseError values, or nil when the response matched
+func (r *ParseReport) Err() error {
+	var errs []error
+	for i := range r.Mismatches {
+		errs = append(errs, &r.Mismatches[i])
+	}
+	for _, f := range r.Fields {
+		if !f.drifted() {
+			continue
+		}
+		e := &ParseError{Endpoint: r.Endpoint, Source: r.Source, Path: f.Path, Field: f.Field, Msg: "not found in any record"}
+		if f.WrongType > 0 {
+			e.Msg = fmt.Sprintf("got %s, want %s", f.Got, f.Kind)
+		}
+		errs = append(errs, e)
+	}
+	return errors.Join(errs...)
+}
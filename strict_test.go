@@ -0,0 +1,189 @@
+package googleplayscraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kryuchenko/google-play-scraper/batchexecute"
+)
+
+func fixtureClient(t *testing.T, body []byte, opts ...ClientOption) *Client {
+	t.Helper()
+	return NewClient(append(opts, WithTransport(&fixtureTransport{body: body}))...)
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return body
+}
+
+func TestStrictAcceptsFixtures(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := fixtureClient(t, readFixture(t, "details_full.html"), WithStrict()).App(ctx, "com.anvil.ledger", AppOptions{}); err != nil {
+		t.Errorf("App: %v", err)
+	}
+	if _, err := fixtureClient(t, readFixture(t, "preregister.html"), WithStrict()).App(ctx, "com.nebula.starfall", AppOptions{}); err != nil {
+		t.Errorf("pre-registration App: %v", err)
+	}
+	if _, err := fixtureClient(t, readFixture(t, "top_charts.html"), WithStrict()).List(ctx, ListOptions{Collection: CollectionTopPaid}); err != nil {
+		t.Errorf("List: %v", err)
+	}
+	if _, err := fixtureClient(t, readFixture(t, "search.html"), WithStrict()).Search(ctx, SearchOptions{Term: "ledger"}); err != nil {
+		t.Errorf("Search: %v", err)
+	}
+	if _, err := fixtureClient(t, readFixture(t, "reviews_rpc.txt"), WithStrict()).Reviews(ctx, "com.anvil.ledger", ReviewOptions{}); err != nil {
+		t.Errorf("Reviews: %v", err)
+	}
+	if _, err := fixtureClient(t, readFixture(t, "datasafety.html"), WithStrict()).DataSafety(ctx, DataSafetyOptions{AppID: "com.anvil.ledger"}); err != nil {
+		t.Errorf("DataSafety: %v", err)
+	}
+}
+
+func TestStrictFieldMismatch(t *testing.T) {
+	page := bytes.Replace(readFixture(t, "details_full.html"), []byte(`[["Ledger Pro"]`), []byte(`[[42]`), 1)
+	ctx := context.Background()
+
+	app, err := fixtureClient(t, page).App(ctx, "com.anvil.ledger", AppOptions{})
+	if err != nil || app.Version == "" {
+		t.Fatalf("lenient App = %+v, %v", app, err)
+	}
+
+	_, err = fixtureClient(t, page, WithStrict()).App(ctx, "com.anvil.ledger", AppOptions{})
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("got %v, want *ParseError", err)
+	}
+	if perr.Field != "Title" || perr.Path != "ds:5[1][2][0][0]" || perr.Endpoint != EndpointDetails {
+		t.Errorf("ParseError = %+v", perr)
+	}
+	if !strings.Contains(err.Error(), "got number, want string") {
+		t.Errorf("message = %q", err)
+	}
+}
+
+func TestStrictSectionFallback(t *testing.T) {
+	body := readFixture(t, "top_charts.html")
+	ctx := context.Background()
+
+	// Top free is empty in the fixture, so the lenient parser falls back to top paid
+	results, err := fixtureClient(t, body).List(ctx, ListOptions{})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("lenient List = %d results, %v", len(results), err)
+	}
+
+	_, err = fixtureClient(t, body, WithStrict()).List(ctx, ListOptions{})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Path != "ds:4[0][1][0][21][0]" {
+		t.Errorf("got %v, want section mismatch", err)
+	}
+}
+
+func TestStrictSkippedReview(t *testing.T) {
+	var buf bytes.Buffer
+	data := json.RawMessage(`[[["gp:1",["Ann"],5,null,"Fine",[1717000000],0],"bogus"]]`)
+	batchexecute.WriteResponse(&buf, batchexecute.Result{RPCID: "oCPfdb", Data: data})
+	ctx := context.Background()
+
+	result, err := fixtureClient(t, buf.Bytes()).Reviews(ctx, "com.example", ReviewOptions{})
+	if err != nil || len(result.Reviews) != 1 {
+		t.Fatalf("lenient Reviews = %+v, %v", result, err)
+	}
+
+	_, err = fixtureClient(t, buf.Bytes(), WithStrict()).Reviews(ctx, "com.example", ReviewOptions{})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Path != "oCPfdb[0][1]" {
+		t.Errorf("got %v, want skipped review at oCPfdb[0][1]", err)
+	}
+}
+
+func TestParseReportNestedLocation(t *testing.T) {
+	page := bytes.Replace(readFixture(t, "datasafety.html"), []byte(`"Email address"`), []byte(`7`), 1)
+
+	rep := &ParseReport{}
+	if _, err := parseDataSafetyPage(page, rep); err != nil {
+		t.Fatalf("parseDataSafetyPage: %v", err)
+	}
+	var perr *ParseError
+	if err := rep.Err(); !errors.As(err, &perr) {
+		t.Fatalf("got %v, want *ParseError", err)
+	}
+	if perr.Field != "Data" || perr.Path != "ds:3[1][2][1][138][4][1][0][0][4][0][0]" {
+		t.Errorf("ParseError = %+v", perr)
+	}
+}
+
+func TestStrictNumberConversion(t *testing.T) {
+	appData := make([]interface{}, 80)
+	appData[0] = []interface{}{"Example"}
+	appData[51] = []interface{}{[]interface{}{"4,5", "4,5"}}
+	appData[78] = []interface{}{[]interface{}{
+		[]interface{}{nil, nil, []interface{}{"wide", float64(720)}, []interface{}{nil, nil, "https://example.com/shot"}},
+	}}
+
+	rep := &ParseReport{}
+	if _, err := parseAppPage(appPage(t, appData, ""), "com.example", "url", rep); err != nil {
+		t.Fatalf("parseAppPage: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, m := range rep.Mismatches {
+		got[m.Field] = m.Path
+	}
+	if got["Score"] != "ds:5[1][2][51][0][1]" {
+		t.Errorf("Score conversion reported at %q", got["Score"])
+	}
+	if got["Media"] != "ds:5[1][2][78][0][0][2][0]" {
+		t.Errorf("screenshot width conversion reported at %q", got["Media"])
+	}
+}
+
+func TestStrictSkippedEnrichment(t *testing.T) {
+	results := []SearchResult{{AppID: "com.example"}}
+	ctx := context.Background()
+
+	enriched, err := fixtureClient(t, []byte("<html></html>")).enrichSearchResults(ctx, results, "en", "us")
+	if err != nil || len(enriched) != 1 {
+		t.Fatalf("lenient enrichment = %+v, %v", enriched, err)
+	}
+
+	_, err = fixtureClient(t, []byte("<html></html>"), WithStrict()).enrichSearchResults(ctx, results, "en", "us")
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Path != "results[0]" || perr.Endpoint != EndpointDetails {
+		t.Errorf("got %v, want skipped enrichment of results[0]", err)
+	}
+}
+
+func TestStrictPermissionsAndSuggest(t *testing.T) {
+	perms := rpcEnvelope(t, "xdSrCf", []interface{}{
+		[]interface{}{[]interface{}{"Location", nil, []interface{}{[]interface{}{nil, "precise location"}, "bogus"}}},
+	})
+	suggest := rpcEnvelope(t, "IJ4APc", []interface{}{[]interface{}{[]interface{}{[]interface{}{"maps"}, "bogus"}}})
+	ctx := context.Background()
+
+	if got, err := fixtureClient(t, perms).Permissions(ctx, PermissionsOptions{AppID: "com.example"}); err != nil || len(got) != 1 {
+		t.Fatalf("lenient Permissions = %+v, %v", got, err)
+	}
+	_, err := fixtureClient(t, perms, WithStrict()).Permissions(ctx, PermissionsOptions{AppID: "com.example"})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Path != "xdSrCf[0][0][2][1]" {
+		t.Errorf("got %v, want skipped permission at xdSrCf[0][0][2][1]", err)
+	}
+
+	if got, err := fixtureClient(t, suggest).Suggest(ctx, SuggestOptions{Term: "ma"}); err != nil || len(got) != 1 {
+		t.Fatalf("lenient Suggest = %v, %v", got, err)
+	}
+	_, err = fixtureClient(t, suggest, WithStrict()).Suggest(ctx, SuggestOptions{Term: "ma"})
+	if !errors.As(err, &perr) || perr.Path != "IJ4APc[0][0][1]" {
+		t.Errorf("got %v, want skipped suggestion at IJ4APc[0][0][1]", err)
+	}
+}
@@ -2,9 +2,9 @@ package googleplayscraper
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/url"
+
+	"github.com/kryuchenko/google-play-scraper/batchexecute"
 )
 
 // SuggestOptions configures the search suggestions request
@@ -14,8 +14,24 @@ type SuggestOptions struct {
 	Country string
 }
 
+// SuggestResult contains search suggestions with the data they were read from
+type SuggestResult struct {
+	Suggestions []string `json:"suggestions"`
+	Raw         *RawData `json:"raw,omitempty"` // Set with WithRawData
+}
+
 // Suggest returns search suggestions for a query
 func (c *Client) Suggest(ctx context.Context, opts SuggestOptions) ([]string, error) {
+	result, err := c.SuggestResults(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result.Suggestions, nil
+}
+
+// SuggestResults is Suggest returning a SuggestResult, whose Raw field holds
+// the decoded RPC data when WithRawData is set
+func (c *Client) SuggestResults(ctx context.Context, opts SuggestOptions) (*SuggestResult, error) {
 	if opts.Term == "" {
 		return nil, fmt.Errorf("term is required")
 	}
@@ -27,74 +43,61 @@ func (c *Client) Suggest(ctx context.Context, opts SuggestOptions) ([]string, er
 		opts.Country = "us"
 	}
 
-	reqURL := fmt.Sprintf("%s/_/PlayStoreUi/data/batchexecute?rpcids=IJ4APc&hl=%s&gl=%s",
-		BaseURL, opts.Lang, opts.Country)
-
-	term := url.QueryEscape(opts.Term)
-	body := fmt.Sprintf(`f.req=%%5B%%5B%%5B%%22IJ4APc%%22%%2C%%22%%5B%%5Bnull%%2C%%5B%%5C%%22%s%%5C%%22%%5D%%2C%%5B10%%5D%%2C%%5B2%%5D%%2C4%%5D%%5D%%22%%5D%%5D%%5D`, term)
-
-	respBody, err := c.post(ctx, reqURL, "application/x-www-form-urlencoded;charset=UTF-8", body)
+	args := []any{[]any{nil, []any{opts.Term}, []any{10}, []any{2}, 4}}
+	respBody, err := c.rpc(ctx, opts.Lang, opts.Country, batchexecute.Call{RPCID: "IJ4APc", Args: args})
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	return parseSuggestResponse(respBody)
-}
-
-func parseSuggestResponse(body []byte) ([]string, error) {
-	// Skip the )]}'  prefix
-	start := 0
-	for i := range body {
-		if body[i] == '\n' {
-			start = i + 1
-			break
-		}
-	}
-
-	if start >= len(body) {
-		return nil, fmt.Errorf("invalid response")
+	rep := c.newReport(EndpointSuggest, "IJ4APc")
+	result, err := parseSuggestResponse(respBody, rep)
+	if err != nil {
+		return nil, err
 	}
-
-	var outer [][]interface{}
-	if err := json.Unmarshal(body[start:], &outer); err != nil {
+	if err := c.sendReport(rep, len(result.Suggestions)); err != nil {
 		return nil, err
 	}
-
-	if len(outer) == 0 || len(outer[0]) < 3 {
-		return nil, nil
+	if !c.rawData {
+		result.Raw = nil
 	}
+	return result, nil
+}
 
-	dataStr, ok := outer[0][2].(string)
-	if !ok {
-		return nil, nil
-	}
+func parseSuggestResponse(body []byte, rep *ParseReport) (*SuggestResult, error) {
+	result := &SuggestResult{}
 
-	var data []interface{}
-	if err := json.Unmarshal([]byte(dataStr), &data); err != nil {
+	data, err := rpcData(body, "IJ4APc")
+	if err != nil {
+		if noRPCData(err) {
+			return result, nil
+		}
 		return nil, err
 	}
 
-	if data == nil {
-		return nil, nil
-	}
-
 	// Suggestions in data[0][0]
 	suggestions := getPath(data, 0, 0)
+	result.Raw = &RawData{RPC: data, Node: suggestions}
 	if suggestions == nil {
-		return nil, nil
+		return result, nil
 	}
 
 	suggestionsArr, ok := suggestions.([]interface{})
 	if !ok {
-		return nil, nil
+		rep.mismatch("IJ4APc[0][0]", "suggestions not found")
+		return result, nil
 	}
 
-	var result []string
-	for _, s := range suggestionsArr {
-		if arr, ok := s.([]interface{}); ok && len(arr) > 0 {
-			if str, ok := arr[0].(string); ok {
-				result = append(result, str)
-			}
+	for i, s := range suggestionsArr {
+		rep.at("IJ4APc[0][0][%d]", i)
+		arr, ok := s.([]interface{})
+		if !ok || len(arr) == 0 {
+			rep.mismatch(rep.location(), "skipped: not a suggestion")
+			continue
+		}
+		var term string
+		applyFields(&term, arr, suggestionFields, rep)
+		if term != "" {
+			result.Suggestions = append(result.Suggestions, term)
 		}
 	}
 
@@ -37,32 +37,33 @@ func TestSuggestIntegration(t *testing.T) {
 
 func TestParseSuggestResponse(t *testing.T) {
 	// Case 1: Invalid JSON (should fail)
-	_, err := parseSuggestResponse([]byte("invalid-json"))
+	_, err := parseSuggestResponse([]byte("invalid-json"), nil)
 	if err == nil {
 		t.Error("expected error for invalid JSON")
 	}
 
 	// Case 2: Valid JSON but missing suggestions array structure
 	// Expected: [0][0][0] = "suggestion"
-	_, err = parseSuggestResponse([]byte(`[[]]`))
+	_, err = parseSuggestResponse([]byte(`[[]]`), nil)
 	// Depending on implementation, might return empty or error.
 	// suggest.go:44 unmarshals to array.
 	// If structures nested don't match, it usually returns nil suggestions without error or error if strict.
 
 	// Case 3: Proper structure
-	// parseSuggestResponse expects outer JSON: [["wrb.fr", "rpcId", "INNER_JSON_STRING", "generic"]]
+	// parseSuggestResponse expects outer JSON: [["wrb.fr", "IJ4APc", "INNER_JSON_STRING", "generic"]]
 	// INNER_JSON_STRING: [ [ ["suggestion1"], ["suggestion2"] ] ]
 	// suggest.go:82 suggestions := getPath(data, 0, 0)
 
 	innerJSON := `[[[["suggestion1"], ["suggestion2"]]]]`
 	// We need to escape quotes in innerJSON for the outer JSON string
 	validBody := fmt.Sprintf(`)]}'
-[["wrb.fr","rpcId","%s","generic"]]`, strings.ReplaceAll(innerJSON, `"`, `\"`))
+[["wrb.fr","IJ4APc","%s","generic"]]`, strings.ReplaceAll(innerJSON, `"`, `\"`))
 
-	suggestions, err := parseSuggestResponse([]byte(validBody))
+	result, err := parseSuggestResponse([]byte(validBody), nil)
 	if err != nil {
-		t.Errorf("unexpected error: %v", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
+	suggestions := result.Suggestions
 	if len(suggestions) != 2 {
 		t.Errorf("expected 2 suggestions, got %d", len(suggestions))
 	}
@@ -16,6 +16,7 @@ type Review struct {
 	ThumbsUp     int       `json:"thumbsUp"`
 	URL          string    `json:"url"`
 	Criterias    []Criteria `json:"criterias,omitempty"`
+	Raw          *RawData  `json:"raw,omitempty"` // Set with WithRawData
 }
 
 // Criteria represents review criteria (e.g., gameplay, graphics)
@@ -52,42 +53,83 @@ func DefaultReviewOptions() ReviewOptions {
 
 // App represents application details
 type App struct {
-	AppID            string   `json:"appId"`
-	Title            string   `json:"title"`
-	Summary          string   `json:"summary"`
-	Description      string   `json:"description"`
-	DescriptionHTML  string   `json:"descriptionHTML"`
-	Developer        string   `json:"developer"`
-	DeveloperID      string   `json:"developerId"`
-	DeveloperEmail   string   `json:"developerEmail"`
-	DeveloperWebsite string   `json:"developerWebsite"`
-	DeveloperAddress string   `json:"developerAddress"`
-	Icon             string   `json:"icon"`
-	Score            float64  `json:"score"`
-	ScoreText        string   `json:"scoreText"`
-	Ratings          int      `json:"ratings"`
-	Reviews          int      `json:"reviews"`
-	Histogram        [5]int   `json:"histogram"`
-	Price            float64  `json:"price"`
-	PriceText        string   `json:"priceText"`
-	Currency         string   `json:"currency"`
-	Free             bool     `json:"free"`
-	Installs         string   `json:"installs"`
-	MinInstalls      int64    `json:"minInstalls"`
-	MaxInstalls      int64    `json:"maxInstalls"`
-	Genre            string   `json:"genre"`
-	GenreID          string   `json:"genreId"`
-	Categories       []string `json:"categories"`
-	Version          string   `json:"version"`
-	AndroidVersion   string   `json:"androidVersion"`
-	ContentRating    string   `json:"contentRating"`
-	Released         string   `json:"released"`
-	Updated          int64    `json:"updated"`
-	URL              string   `json:"url"`
-	Screenshots      []string `json:"screenshots"`
-	Video            string   `json:"video,omitempty"`
-	VideoImage       string   `json:"videoImage,omitempty"`
-	HeaderImage      string   `json:"headerImage,omitempty"`
-	PrivacyPolicy    string   `json:"privacyPolicy,omitempty"`
-	Available        bool     `json:"available"`
+	AppID            string       `json:"appId"`
+	Title            string       `json:"title"`
+	Summary          string       `json:"summary"`
+	Description      string       `json:"description"`
+	DescriptionHTML  string       `json:"descriptionHTML"`
+	Developer        string       `json:"developer"`
+	DeveloperID      string       `json:"developerId"`
+	DeveloperEmail   string       `json:"developerEmail"`
+	DeveloperWebsite string       `json:"developerWebsite"`
+	DeveloperAddress string       `json:"developerAddress"`
+	Icon             string       `json:"icon"`
+	Score            float64      `json:"score"`
+	ScoreText        string       `json:"scoreText"`
+	Ratings          int          `json:"ratings"`
+	Reviews          int          `json:"reviews"`
+	Histogram        [5]int       `json:"histogram"`
+	Price            float64      `json:"price"`
+	PriceText        string       `json:"priceText"`
+	Currency         string       `json:"currency"`
+	Free             bool         `json:"free"`
+	Installs         string       `json:"installs"`
+	MinInstalls      int64        `json:"minInstalls"`
+	MaxInstalls      int64        `json:"maxInstalls"`
+	Genre            string       `json:"genre"`
+	GenreID          string       `json:"genreId"`
+	Categories       []string     `json:"categories"`
+	Version          string       `json:"version"`
+	AndroidVersion   string       `json:"androidVersion"`
+	ContentRating    string       `json:"contentRating"`
+	Released         string       `json:"released"`
+	Updated          int64        `json:"updated"`
+	URL              string       `json:"url"`
+	Screenshots      []string     `json:"screenshots"`
+	Video            string       `json:"video,omitempty"`
+	VideoImage       string       `json:"videoImage,omitempty"`
+	HeaderImage      string       `json:"headerImage,omitempty"`
+	PrivacyPolicy    string       `json:"privacyPolicy,omitempty"`
+	Available        bool         `json:"available"`
+	Availability     Availability `json:"availability"`
+	PreRegister      bool         `json:"preRegister"`
+	Media            Media        `json:"media"`
+	Raw              *RawData     `json:"raw,omitempty"` // Set with WithRawData
+}
+
+// Availability describes whether an app listing can be installed
+type Availability string
+
+const (
+	AvailabilityAvailable        Availability = "available"
+	AvailabilityNotFound         Availability = "not_found"         // Removed or never published
+	AvailabilityRegionRestricted Availability = "region_restricted" // Not offered in the requested country
+	AvailabilityIncompatible     Availability = "incompatible"      // No supported devices
+	AvailabilityPreRegistration  Availability = "pre_registration"  // Listed but not yet released
+	AvailabilityUnavailable      Availability = "unavailable"       // Listed but not installable for another reason
+)
+
+// FormFactor identifies the device class a set of screenshots targets.
+// Only phone screenshots are read so far: tablet, Chromebook and Wear sets
+// need captured device-specific listings to confirm where they live.
+type FormFactor string
+
+const (
+	FormFactorPhone FormFactor = "phone"
+)
+
+// Image is a store listing image with its intrinsic dimensions
+type Image struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// Media contains the artwork and video of a store listing
+type Media struct {
+	Icon        Image                  `json:"icon"`
+	HeaderImage Image                  `json:"headerImage"` // Feature graphic
+	Video       string                 `json:"video,omitempty"`
+	VideoImage  Image                  `json:"videoImage"`
+	Screenshots map[FormFactor][]Image `json:"screenshots,omitempty"`
 }